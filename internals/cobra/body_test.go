@@ -0,0 +1,71 @@
+package cobra
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github/pm/pkg/fileSystem"
+)
+
+func readIssueBody(t *testing.T, repo string, fileName string) string {
+	t.Helper()
+
+	fs := fileSystem.NewFileSystem(filepath.Join(repo, fileSystem.PROJECT_DIRECTORY))
+	if bootErr := fs.Boot(); bootErr != nil {
+		t.Fatal(bootErr)
+	}
+
+	body, bodyErr := fs.RetrieveFileContents(fileName)
+	if bodyErr != nil {
+		t.Fatal(bodyErr)
+	}
+
+	return body
+}
+
+func TestCreateLeavesPipedStdinAlone(t *testing.T) {
+	repo := bootRepository(t)
+	mustRun(t, "init")
+
+	// while read name; do pm create "$name" --type task; done < names
+	reader, writer, pipeErr := os.Pipe()
+	if pipeErr != nil {
+		t.Fatal(pipeErr)
+	}
+	writer.WriteString("Logout\n")
+	writer.Close()
+
+	stdin := os.Stdin
+	os.Stdin = reader
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		reader.Close()
+	})
+
+	mustRun(t, "create", "Login", "--type", "task")
+	if body := readIssueBody(t, repo, "Login"); strings.Contains(body, "Logout") {
+		t.Errorf("create read its body from stdin: %q", body)
+	}
+
+	if rest, _ := io.ReadAll(reader); string(rest) != "Logout\n" {
+		t.Errorf("create consumed stdin, %q is left", rest)
+	}
+
+	createCmd, _, findErr := rootCmd.Find([]string{"create"})
+	if findErr != nil {
+		t.Fatal(findErr)
+	}
+	t.Cleanup(func() {
+		createCmd.Flags().Set("body-file", "")
+		rootCmd.SetIn(nil)
+	})
+
+	rootCmd.SetIn(strings.NewReader("# Logout\n\nEnds the session\n"))
+	mustRun(t, "create", "Logout", "--type", "task", "--body-file", "-")
+	if body := readIssueBody(t, repo, "Logout"); !strings.Contains(body, "Ends the session") {
+		t.Errorf("create did not read --body-file -, the body is %q", body)
+	}
+}
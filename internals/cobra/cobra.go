@@ -2,569 +2,681 @@ package cobra
 
 import (
 	"fmt"
+	"io"
+	"log"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 
-	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/lipgloss/table"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
-	"github/pm/pkg/dag"
-	"github/pm/pkg/fileManager"
-	"github/pm/pkg/trie"
+	"github/pm/internals/ui/application"
+	"github/pm/pkg/config"
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+	"github/pm/pkg/importer"
+	"github/pm/pkg/site"
 )
 
+// Output format of the read commands, see docs/cli.md
+var output string
+
+// Directory containing the .pm directory, overrides discovery
+var project string
+
 var rootCmd = &cobra.Command{
-	Use:   "pm",
-	Short: "pm is your best friend",
-	Run: func(cmd *cobra.Command, args []string) {
-		log.Println("Root Command")
+	Use:           "pm",
+	Short:         "pm is your best friend",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applySettings()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runApplication()
 	},
 }
 
 func init() {
-	var eValues, sValues, tValues []string
-
 	var initCmd = &cobra.Command{
 		Use:   "init",
 		Short: "Initialize a new .pm project",
-		Run: func(cmd *cobra.Command, args []string) {
-			info, err := os.Stat("./.pm")
-			if !os.IsNotExist(err) {
-				if info.IsDir() {
-					log.Printf("Directory already is managed by pm")
-					return
-				}
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, rootErr := configuredRoot()
+			if rootErr != nil {
+				return rootErr
 			}
 
-			err = os.Mkdir("./.pm", os.ModePerm)
-			if err != nil && !os.IsExist(err) {
-				log.Printf("Error creating pm directory: %v\n", err)
+			if root == "" {
+				root = filepath.Join(".", fileSystem.PROJECT_DIRECTORY)
 			}
 
-			err = os.Mkdir("./.pm/blobs", os.ModePerm)
-			if err != nil && !os.IsExist(err) {
-				log.Printf("Error creating blobs directory: %v\n", err)
+			if fileSystem.IsProjectRoot(root) {
+				fmt.Fprintln(cmd.OutOrStdout(), "Directory already is managed by pm")
+				return nil
 			}
 
-			err = os.Mkdir("./.pm/trie", os.ModePerm)
-			if err != nil && !os.IsExist(err) {
-				log.Printf("Error creating trie directory: %v\n", err)
+			mkdirErr := os.MkdirAll(filepath.Join(root, "blobs"), os.ModePerm)
+			if mkdirErr != nil {
+				return mkdirErr
 			}
 
-			epicFile, epicErr := os.Create("./.pm/trie/epic")
-			if epicErr != nil {
-				log.Printf("Error creating epic trie: %v\n", epicErr)
+			fs, bootErr := bootFileSystemAt(root)
+			if bootErr != nil {
+				return bootErr
 			}
-			epicTrie := trie.NewReconcilableTrie("epic")
-			epicTrie.SaveReconcilable()
 
-			defer epicFile.Close()
+			fmt.Fprintln(cmd.OutOrStdout(), "Initialized pm project in "+root)
+			return fs.ShutDown()
+		},
+	}
+
+	var createType, createParent, createTitle, createBodyFile string
+	var createDependsOn []string
+	var createCmd = &cobra.Command{
+		Use:   "create [issue]",
+		Short: "Create an issue",
+		Long:  "Create an epic, story or task, optionally under a parent issue and depending on other issues. The name is given as an argument or with --title. The body is read from --body-file, - for stdin",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			fileName := createTitle
+			if len(args) == 1 {
+				if createTitle != "" && createTitle != args[0] {
+					return fileSystem.InvalidError("Issue name given twice: " + args[0] + " and --title " + createTitle)
+				}
 
-			storyFile, storyErr := os.Create("./.pm/trie/story")
-			if storyErr != nil {
-				log.Printf("Error creating story trie: %v\n", storyErr)
+				fileName = args[0]
 			}
-			storyTrie := trie.NewReconcilableTrie("story")
-			storyTrie.SaveReconcilable()
 
-			defer storyFile.Close()
+			if fileName == "" {
+				return fileSystem.InvalidError("Issue name is required, pass it as an argument or with --title")
+			}
 
-			taskFile, taskErr := os.Create("./.pm/trie/task")
-			if taskErr != nil {
-				log.Printf("Error creating task trie: %v\n", taskErr)
+			body, bodyErr := readBody(cmd, createBodyFile)
+			if bodyErr != nil {
+				return bodyErr
 			}
-			taskTrie := trie.NewReconcilableTrie("task")
-			taskTrie.SaveReconcilable()
 
-			defer taskFile.Close()
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
+			defer shutDown(fs, &runErr)
 
-			err = os.Mkdir("./.pm/dag", os.ModePerm)
-			if err != nil && !os.IsExist(err) {
-				log.Printf("Error creating dag directory: %v\n", err)
+			return fs.CreateIssue(fileName, createType, createParent, createDependsOn, body)
+		},
+	}
+
+	var relationship string
+	var linkCmd = &cobra.Command{
+		Use:   "link <parent> <child>",
+		Short: "Link two issues",
+		Long:  "Link two issues. With --relationship hierarchy parent contains child, with --relationship dependency parent blocks child",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			label, labelErr := fileSystem.ParseRelationship(relationship)
+			if labelErr != nil {
+				return labelErr
 			}
-			pmDag := dag.NewReconcilableDag("pmDag")
-			pmDag.SaveReconcilable()
 
-			tmpFile, tmpErr := os.Create("./.pm/tmp")
-			if tmpErr != nil {
-				log.Printf("Error creating tmp file: %v\n", tmpErr)
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
+			defer shutDown(fs, &runErr)
 
-			defer tmpFile.Close()
+			return fs.Link(args[0], args[1], label)
+		},
+	}
 
-			err = os.Mkdir("./.pm/remote", os.ModePerm)
-			if err != nil && !os.IsExist(err) {
-				log.Printf("Error creating remote directory: %v\n", err)
+	var unlinkCmd = &cobra.Command{
+		Use:   "unlink <parent> <child>",
+		Short: "Remove the link between two issues",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			label, labelErr := fileSystem.ParseRelationship(relationship)
+			if labelErr != nil {
+				return labelErr
 			}
 
-			remoteDeltaFile, remoteDeltaErr := os.Create("./.pm/remote/dag")
-			if remoteDeltaErr != nil {
-				log.Printf("Error creating delta file: %v\n", remoteDeltaErr)
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
-			remoteDeltaTree := dag.NewReconcilableDag("dag")
-			remoteDeltaTree.SaveReconcilable()
+			defer shutDown(fs, &runErr)
 
-			defer remoteDeltaFile.Close()
+			return fs.Unlink(args[0], args[1], label)
 		},
 	}
 
-	var deleteCmd = &cobra.Command{
-		Use:   "delete",
-		Short: "Delete epics, stories or tasks",
-		Run: func(cmd *cobra.Command, args []string) {
-			epics := len(eValues)
-			stories := len(sValues)
-			tasks := len(tValues)
-
-			reconcilable := dag.LoadReconcilableDag("./.pm/dag/pmDag")
-			pmDag := reconcilable.DataStructure.(*dag.Dag)
-			defer reconcilable.SaveReconcilable()
-
-			if epics > 0 {
-				for _, value := range eValues {
-					// Create a Delete Vertex Alpha
-					existingVertex := pmDag.RetrieveVertex(value)
-					deleteVertexAlpha := &dag.RemoveVertexAlpha{
-						Target: existingVertex,
-					}
-					// Update datastructure
-					reconcilable.Commit(deleteVertexAlpha)
-				}
+	var listType, listStatus string
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List issues with their type and status",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
 
+			issues, listErr := listIssues(fs, listType, listStatus)
+			if listErr != nil {
+				return listErr
 			}
 
-			if stories > 0 {
-				for _, value := range sValues {
-					// Create a Delete Vertex Alpha
-					existingVertex := pmDag.RetrieveVertex(value)
-					deleteVertexAlpha := &dag.RemoveVertexAlpha{
-						Target: existingVertex,
-					}
-					// Update datastructure
-					reconcilable.Commit(deleteVertexAlpha)
-				}
+			return writeIssueList(cmd.OutOrStdout(), issues)
+		},
+	}
 
+	var queryIssue issueQuery
+	var queryCmd = &cobra.Command{
+		Use:   "query [text]",
+		Short: "Find issues by text, type, status, priority and place in the hierarchy",
+		Long:  "List the issues whose name or body contains text, ignoring case, and that match every filter given. The output is the same as pm list",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
 
-			if tasks > 0 {
-				for _, value := range tValues {
-					// Create a Delete Vertex Alpha
-					existingVertex := pmDag.RetrieveVertex(value)
-					deleteVertexAlpha := &dag.RemoveVertexAlpha{
-						Target: existingVertex,
-					}
-					// Update datastructure
-					reconcilable.Commit(deleteVertexAlpha)
-				}
+			query := queryIssue
+			if len(args) == 1 {
+				query.Text = args[0]
+			}
 
+			issues, queryErr := queryIssues(fs, listType, listStatus, query)
+			if queryErr != nil {
+				return queryErr
 			}
 
-			reconcilableEpicTrie := trie.LoadReconcilableTrie("./.pm/trie/epic")
-			epicTrie := reconcilableEpicTrie.DataStructure.(*trie.Trie)
-			defer reconcilableEpicTrie.SaveReconcilable()
-			for _, value := range eValues {
-				// Create a Delete Word Alpha
-				word, epicErr := epicTrie.RetrieveValue(value)
-				if word == "" && epicErr != nil {
-					continue
-				}
+			return writeIssueList(cmd.OutOrStdout(), issues)
+		},
+	}
 
-				// Update dataStructure
-				deleteWordAlpha := trie.RemoveTrieNodeAlpha{
-					FileName: value,
-				}
+	var showCmd = &cobra.Command{
+		Use:   "show <issue>",
+		Short: "Show the details, relationships and body of an issue",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
 
-				reconcilableEpicTrie.Commit(deleteWordAlpha)
+			issue, issueErr := showIssue(fs, args[0])
+			if issueErr != nil {
+				return issueErr
+			}
 
+			fields := [][]string{
+				{"name", issue.Name},
+				{"type", issue.Type},
+				{"status", issue.Status},
+				{"priority", issue.Priority},
+				{"estimate", formatEstimate(issue.Estimate)},
+				{"parents", strings.Join(issue.Parents, ", ")},
+				{"children", strings.Join(issue.Children, ", ")},
+				{"blocked_by", strings.Join(issue.BlockedBy, ", ")},
+				{"blocks", strings.Join(issue.Blocks, ", ")},
 			}
 
-			reconcilableStoryTrie := trie.LoadReconcilableTrie("./.pm/trie/story")
-			storyTrie := reconcilableStoryTrie.DataStructure.(*trie.Trie)
-			defer reconcilableStoryTrie.SaveReconcilable()
-			for _, value := range sValues {
-				// Create a Delete Word Alpha
-				word, storyErr := storyTrie.RetrieveValue(value)
-				if word == "" && storyErr != nil {
-					continue
+			rows := append(fields, []string{"body", issue.Body})
+			return writeOutput(cmd.OutOrStdout(), output, issue, rows, func(out io.Writer) {
+				labels := []string{"Name", "Type", "Status", "Priority", "Estimate", "Parents", "Children", "Blocked by", "Blocks"}
+				for index, field := range fields {
+					fmt.Fprintf(out, "%-11s %s\n", labels[index], field[1])
 				}
 
-				// Update dataStructure
-				deleteWordAlpha := trie.RemoveTrieNodeAlpha{
-					FileName: value,
+				if issue.Body != "" {
+					fmt.Fprint(out, "\n"+issue.Body)
 				}
+			})
+		},
+	}
 
-				reconcilableStoryTrie.Commit(deleteWordAlpha)
-			}
-
-			reconcilableTaskTrie := trie.LoadReconcilableTrie("./.pm/trie/task")
-			taskTrie := reconcilableTaskTrie.DataStructure.(*trie.Trie)
-			defer reconcilableTaskTrie.SaveReconcilable()
-			for _, value := range tValues {
-				// Create a Delete Word Alpha
-				word, taskErr := taskTrie.RetrieveValue(value)
-				if word == "" && taskErr != nil {
-					continue
+	var editBodyFile string
+	var editCmd = &cobra.Command{
+		Use:   "edit <issue>",
+		Short: "Open the body of an issue in $EDITOR",
+		Long:  "Open the body of an issue in $EDITOR. With --body-file the body is replaced by the file, - for stdin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			if editBodyFile != "" {
+				body, readErr := readInput(cmd, editBodyFile)
+				if readErr != nil {
+					return readErr
 				}
 
-				// Update dataStructure
-				deleteWordAlpha := trie.RemoveTrieNodeAlpha{
-					FileName: value,
+				fs, bootErr := bootFileSystem()
+				if bootErr != nil {
+					return bootErr
 				}
+				defer shutDown(fs, &runErr)
 
-				reconcilableStoryTrie.Commit(deleteWordAlpha)
+				return fs.SetIssueBody(args[0], string(body))
 			}
 
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
+			defer shutDown(fs, &runErr)
+
+			_, typeErr := fs.GetFileType(args[0])
+			if typeErr != nil {
+				return typeErr
+			}
+
+			fs.EditFile(args[0])
+			return nil
 		},
 	}
 
-	var addCmd = &cobra.Command{
-		Use:   "add",
-		Short: "Add epics, stories or tasks",
-		Run: func(cmd *cobra.Command, args []string) {
-			epics := len(eValues)
-			stories := len(sValues)
-			tasks := len(tValues)
-
-			reconcilable := dag.LoadReconcilableDag("./.pm/dag/pmDag")
-			defer reconcilable.SaveReconcilable()
-
-			if epics > 0 {
-				for _, value := range eValues {
-					epic := dag.NewVertex(value)
-					alpha := &dag.AddVertexAlpha{
-						Target: epic,
-					}
+	var cascade, reparent bool
 
-					reconcilable.Commit(alpha)
-				}
+	var deleteCmd = &cobra.Command{
+		Use:   "delete <issue>",
+		Short: "Delete an issue",
+		Long:  "Delete an issue. Issues with child issues are only deleted when --cascade or --reparent is given",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			if cascade && reparent {
+				return fileSystem.InvalidError("--cascade and --reparent cannot be used together")
+			}
 
+			mode := fileSystem.DELETE_MODE_REFUSE
+			if cascade {
+				mode = fileSystem.DELETE_MODE_CASCADE
+			} else if reparent {
+				mode = fileSystem.DELETE_MODE_REPARENT
 			}
 
-			if stories > 0 {
-				for _, value := range sValues {
-					story := dag.NewVertex(value)
-					alpha := &dag.AddVertexAlpha{
-						Target: story,
-					}
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
+			defer shutDown(fs, &runErr)
 
-					reconcilable.Commit(alpha)
-				}
+			return fs.DeleteIssue(args[0], mode)
+		},
+	}
+
+	var moveTo string
 
+	var moveCmd = &cobra.Command{
+		Use:   "move <issue>",
+		Short: "Move an issue under a new parent",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
+			defer shutDown(fs, &runErr)
 
-			if tasks > 0 {
-				for _, value := range tValues {
-					task := dag.NewVertex(value)
-					alpha := &dag.AddVertexAlpha{
-						Target: task,
-					}
+			return fs.MoveIssue(args[0], moveTo)
+		},
+	}
 
-					reconcilable.Commit(alpha)
-				}
+	var nextLimit int
+
+	var nextCmd = &cobra.Command{
+		Use:   "next",
+		Short: "List the issues that can be worked on now",
+		Long:  "List issues of the lowest type, tasks by default, that are not done and whose upstream dependencies are all done, most urgent first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
 
-			epicTrie := trie.LoadReconcilableTrie("./.pm/trie/epic")
-			for _, value := range eValues {
-				// Create File
-				fileManager.Commit(value, "", epicTrie)
+			ready, readyErr := fs.ListReadyIssues()
+			if readyErr != nil {
+				return readyErr
 			}
 
-			storyTrie := trie.LoadReconcilableTrie("./.pm/trie/story")
-			for _, value := range sValues {
-				// Create File
-				fileManager.Commit(value, "", storyTrie)
+			if nextLimit > 0 && len(ready) > nextLimit {
+				ready = ready[:nextLimit]
 			}
 
-			taskTrie := trie.LoadReconcilableTrie("./.pm/trie/task")
-			for _, value := range tValues {
-				// Create File
-				fileManager.Commit(value, "", taskTrie)
+			issues := []readyIssue{}
+			rows := [][]string{{"name", "type", "priority", "depth"}}
+			for _, issue := range ready {
+				issues = append(issues, readyIssue{Name: issue.Name, Type: issue.Type, Priority: issue.Priority, Depth: issue.Depth})
+				rows = append(rows, []string{issue.Name, issue.Type, issue.Priority, strconv.Itoa(issue.Depth)})
 			}
-		},
-	}
 
-	var linkCmd = &cobra.Command{
-		Use:   "link",
-		Short: "Link epics, stories and tasks",
-		Run: func(cmd *cobra.Command, args []string) {
-			epics := len(eValues)
-			stories := len(sValues)
-			tasks := len(tValues)
-
-			reconcilable := dag.LoadReconcilableDag("./.pm/dag/pmDag")
-			pmDag := reconcilable.DataStructure.(*dag.Dag)
-			defer reconcilable.SaveReconcilable()
-
-			if tasks > 0 {
-				if epics > 1 || stories > 1 {
-					// Not allowed, invalid relationship.
-					log.Println("A task can only belong to 1 story and epic")
-					return
+			return writeOutput(cmd.OutOrStdout(), output, issues, rows, func(out io.Writer) {
+				for _, issue := range ready {
+					fmt.Fprintf(out, "%s\t%s\t%s\n", issue.Name, issue.Type, issue.Priority)
 				}
+			})
+		},
+	}
 
-				if epics == 1 && stories == 1 {
-					// 1 Epic and 1 Story per multiple task entry
-					epicValue := eValues[0]
-					epicVertex := pmDag.RetrieveVertex(epicValue)
-
-					storyValue := sValues[0]
-					storyVertex := pmDag.RetrieveVertex(storyValue)
+	var impactCmd = &cobra.Command{
+		Use:   "impact <issue>",
+		Short: "List the issues an issue blocks and the issues blocking it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
 
-					addEdgeAlpha := dag.AddEdgeAlpha{
-						From: storyVertex,
-						To:   epicVertex,
-					}
+			downstream, downstreamErr := fs.DownstreamClosure(args[0])
+			if downstreamErr != nil {
+				return downstreamErr
+			}
 
-					reconcilable.Commit(&addEdgeAlpha)
+			upstream, upstreamErr := fs.UpstreamClosure(args[0])
+			if upstreamErr != nil {
+				return upstreamErr
+			}
 
-					for _, value := range tValues {
-						taskVertex := pmDag.RetrieveVertex(value)
+			report := impactReport{Issue: args[0], Blocks: append([]string{}, downstream...), BlockedBy: append([]string{}, upstream...)}
+			rows := [][]string{}
+			for _, issue := range report.Blocks {
+				rows = append(rows, []string{"blocks", issue})
+			}
 
-						addEdgeAlpha := dag.AddEdgeAlpha{
-							From: taskVertex,
-							To:   storyVertex,
-						}
+			for _, issue := range report.BlockedBy {
+				rows = append(rows, []string{"blocked_by", issue})
+			}
 
-						reconcilable.Commit(&addEdgeAlpha)
-					}
+			return writeOutput(cmd.OutOrStdout(), output, report, rows, func(out io.Writer) {
+				fmt.Fprintf(out, "Blocks (%d):\n", len(downstream))
+				for _, issue := range downstream {
+					fmt.Fprintln(out, "  "+issue)
 				}
-			} else if stories > 0 {
-				if epics > 1 {
-					// Not allowed, invalid relationship
-					log.Println("A story can only belong to 1 epic")
-					return
+
+				fmt.Fprintf(out, "Blocked by (%d):\n", len(upstream))
+				for _, issue := range upstream {
+					fmt.Fprintln(out, "  "+issue)
 				}
+			})
+		},
+	}
 
-				if epics == 1 {
-					// 1 Epic per multiple story entry
-					epicValue := eValues[0]
-					epicVertex := pmDag.RetrieveVertex(epicValue)
+	var criticalPathCmd = &cobra.Command{
+		Use:   "critical-path [epic]",
+		Short: "Show the chain of dependencies with the most remaining work",
+		Long:  "Show the chain of dependencies with the most remaining estimated work. Done issues count as no work, issues without an estimate as one unit",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
 
-					for _, value := range sValues {
-						storyVertex := pmDag.RetrieveVertex(value)
+			root := ""
+			if len(args) == 1 {
+				root = args[0]
+			}
 
-						addEdgeAlpha := dag.AddEdgeAlpha{
-							From: storyVertex,
-							To:   epicVertex,
-						}
+			path, work, pathErr := fs.CriticalPath(root)
+			if pathErr != nil {
+				return pathErr
+			}
 
-						reconcilable.Commit(&addEdgeAlpha)
-					}
-				}
-			} else {
-				log.Println("Cannot create dependencies between epics")
-				return
+			report := criticalPathReport{Path: []pathStep{}, Total: work}
+			rows := [][]string{}
+			for _, issue := range path {
+				step := pathStep{Name: issue, Estimate: fs.GetFileEstimate(issue)}
+				report.Path = append(report.Path, step)
+				rows = append(rows, []string{"step", step.Name, formatFloat(step.Estimate)})
 			}
 
-		},
-	}
+			rows = append(rows, []string{"total", formatFloat(work)})
+			return writeOutput(cmd.OutOrStdout(), output, report, rows, func(out io.Writer) {
+				for _, step := range report.Path {
+					fmt.Fprintf(out, "%s\t%s\n", step.Name, formatFloat(step.Estimate))
+				}
 
-	var listCmd = &cobra.Command{
-		Use:   "list",
-		Short: "List all nodes of a type",
-		Run: func(cmd *cobra.Command, args []string) {
-			log.Println("Please choose what to display")
+				fmt.Fprintf(out, "Total\t%s\n", formatFloat(work))
+			})
 		},
 	}
 
-	var listEpicsCmd = &cobra.Command{
-		Use:   "epic",
-		Short: "List all epics",
-		Run: func(cmd *cobra.Command, args []string) {
-			epicTrie := trie.LoadReconcilableTrie("./.pm/trie/epic").DataStructure.(*trie.Trie)
-			allEpics, err := epicTrie.LoadAllWords()
-			if err != nil {
-				return
+	var estimateCmd = &cobra.Command{
+		Use:   "estimate <issue> <work>",
+		Short: "Record the estimated work of an issue",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			estimate, parseErr := strconv.ParseFloat(args[1], 64)
+			if parseErr != nil {
+				return fileSystem.InvalidError("Estimate has to be a number: " + args[1])
+			}
+
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
+			defer shutDown(fs, &runErr)
 
-			buildList(allEpics, "Epics")
+			return fs.SetFileEstimate(args[0], estimate)
 		},
 	}
 
-	var listStoriesCmd = &cobra.Command{
-		Use:   "story",
-		Short: "List all story",
-		Run: func(cmd *cobra.Command, args []string) {
-			storyTrie := trie.LoadReconcilableTrie("./.pm/trie/story").DataStructure.(*trie.Trie)
-			allEpics, err := storyTrie.LoadAllWords()
-			if err != nil {
-				return
+	var priorityCmd = &cobra.Command{
+		Use:   "priority <issue> <high|medium|low>",
+		Short: "Set the priority pm next orders ready issues by",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
+			defer shutDown(fs, &runErr)
 
-			buildList(allEpics, "Story")
+			return fs.SetFilePriority(args[0], args[1])
 		},
 	}
 
-	var listTasksCmd = &cobra.Command{
-		Use:   "task",
-		Short: "List all task",
-		Run: func(cmd *cobra.Command, args []string) {
-			taskTrie := trie.LoadReconcilableTrie("./.pm/trie/task").DataStructure.(*trie.Trie)
-			allTasks, err := taskTrie.LoadAllWords()
-			if err != nil {
-				return
+	var lintCmd = &cobra.Command{
+		Use:   "lint",
+		Short: "Find issues whose hierarchy and dependencies contradict each other",
+		Long:  "Find issues whose hierarchy and dependencies contradict each other, like a task that depends on its own epic. Exits with an error if any are found",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
+
+			contradictions := fs.Lint()
+			linted := []lintedContradiction{}
+			rows := [][]string{{"issues", "steps"}}
+			for _, contradiction := range contradictions {
+				linted = append(linted, lintedContradiction{Issues: contradiction.Issues, Steps: contradiction.Steps})
+				rows = append(rows, []string{strings.Join(contradiction.Issues, ", "), contradiction.String()})
 			}
 
-			buildList(allTasks, "Tasks")
+			writeErr := writeOutput(cmd.OutOrStdout(), output, linted, rows, func(out io.Writer) {
+				if len(contradictions) == 0 {
+					fmt.Fprintln(out, "No contradictions found")
+				}
+
+				for _, contradiction := range contradictions {
+					fmt.Fprintln(out, strings.Join(contradiction.Issues, ", "))
+					fmt.Fprintln(out, "  "+contradiction.String())
+				}
+			})
+
+			if writeErr != nil || len(contradictions) == 0 {
+				return writeErr
+			}
+
+			return fileSystem.ConflictError(strconv.Itoa(len(contradictions)) + " contradictions found")
 		},
 	}
 
-	// Create a new command for viewing epics
-	var viewCmd = &cobra.Command{
-		Use:   "view",
-		Short: "View epics, stories or tasks.",
-		Run: func(cmd *cobra.Command, args []string) {
-			epics := len(eValues)
-			stories := len(sValues)
-			tasks := len(tValues)
-			reconcilable := dag.LoadReconcilableDag("./.pm/dag/pmDag")
-			pmDag := reconcilable.DataStructure.(*dag.Dag)
-
-			total := epics + stories + tasks
-			if total > 1 {
-				log.Println("Only allow to list 1 of a kind at a time")
-				return
+	var graphFormat, graphRoot string
+	var graphLabels []string
+	var graphCmd = &cobra.Command{
+		Use:   "graph",
+		Short: "Export the issue graph as graphviz dot or mermaid",
+		Long:  "Export the issue graph as graphviz dot or mermaid. Nodes are styled by type and status, dependencies are drawn as dashed edges. With --output json, yaml or tsv the nodes and edges are listed instead",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var labels []string
+			for _, label := range graphLabels {
+				labels = append(labels, strings.ToUpper(strings.TrimSpace(label)))
 			}
 
-			var node *dag.Vertex
-			var nodeType string
-			var header string
-			var description []byte
-			var err error
-			var childNodeType string
-
-			if epics > 0 {
-				// Simulate getting epic, stories, and tasks data
-				node = pmDag.RetrieveVertex(eValues[0])
-				header = eValues[0]
-				nodeType = "Epic"
-				childNodeType = "Stories"
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
 
-				epicTrie := trie.LoadReconcilableTrie("./.pm/trie/epic")
-				description, err = fileManager.RetrieveContent(header, epicTrie)
-				if err != nil {
-					return
+			if output != OUTPUT_TEXT {
+				report, graphErr := issueGraph(fs, graphRoot, labels)
+				if graphErr != nil {
+					return graphErr
 				}
 
-			} else if stories > 0 {
-				node = pmDag.RetrieveVertex(sValues[0])
-				header = sValues[0]
-				nodeType = "Story"
-				childNodeType = "Tasks"
-
-				storyTrie := trie.LoadReconcilableTrie("./.pm/trie/story")
-				description, err = fileManager.RetrieveContent(header, storyTrie)
-				if err != nil {
-					return
+				rows := [][]string{}
+				for _, node := range report.Nodes {
+					rows = append(rows, []string{"node", node.Name, node.Type, node.Status})
 				}
-			} else if tasks > 0 {
-				node = pmDag.RetrieveVertex(tValues[0])
-				header = tValues[0]
-				nodeType = "Task"
 
-				taskTrie := trie.LoadReconcilableTrie("./.pm/trie/task")
-				description, err = fileManager.RetrieveContent(header, taskTrie)
-				if err != nil {
-					return
+				for _, edge := range report.Edges {
+					rows = append(rows, []string{"edge", edge.From, edge.To, edge.Label})
 				}
-			} else {
-				log.Println("Not sure what you want to display")
-				return
-			}
 
-			nodeKeys := make([]string, len(node.Children))
-			i := 0
-			for k := range node.Children {
-				nodeKeys[i] = k
-				i++
+				return writeOutput(cmd.OutOrStdout(), output, report, rows, nil)
 			}
 
-			buildSection(description, nodeType+": "+header)
-			buildList(nodeKeys, "Related "+childNodeType)
+			graph, exportErr := fs.ExportGraph(graphFormat, graphRoot, labels)
+			if exportErr != nil {
+				return exportErr
+			}
 
+			fmt.Fprint(cmd.OutOrStdout(), graph)
+			return nil
 		},
 	}
 
-	var editCmd = &cobra.Command{
-		Use:   "edit",
-		Short: "Open the ./tmp file in the user's preferred editor",
-		Run: func(cmd *cobra.Command, args []string) {
-			epics := len(eValues)
-			stories := len(sValues)
-			tasks := len(tValues)
-
-			total := epics + stories + tasks
-			if total > 1 {
-				log.Println("Only allow to 1 file at a time")
-				return
+	var exportFormat string
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export every issue and relationship of the project",
+		Long:  "Export every issue with its type, body and metadata and every relationship between issues. The json schema is described in docs/export.md",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if exportFormat != fileSystem.EXPORT_FORMAT_JSON {
+				return fileSystem.InvalidError("Unknown export format " + exportFormat + ", expected json")
 			}
 
-			// Define the path to the file
-			filePath := "./.pm/tmp"
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
 
-			// Get the user's preferred editor from the $EDITOR environment variable
-			editor := os.Getenv("EDITOR")
-			if editor == "" {
-				// Fallback to a default editor if $EDITOR is not set
-				editor = "vim"
+			exported, exportErr := fs.ExportJSON()
+			if exportErr != nil {
+				return exportErr
 			}
 
-			// Open the file in the editor
-			err := openEditor(editor, filePath)
-			if err != nil {
-				return
+			_, writeErr := cmd.OutOrStdout().Write(exported)
+			return writeErr
+		},
+	}
+
+	var importCmd = &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import issues from a pm export, use - to read from stdin",
+		Long:  "Import issues and relationships from a file written by pm export. Nothing is imported if an issue already exists or any relationship can't be created",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			data, readErr := readInput(cmd, args[0])
+			if readErr != nil {
+				return readErr
 			}
 
-			// Read the file contents after the user has saved and exited
-			fileContent, err := os.Open(filePath)
-			if err != nil {
-				return
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
-			defer fileContent.Close()
+			defer shutDown(fs, &runErr)
 
-			// Print the file content (or you can process it as needed)
-			content, err := os.ReadFile(filePath)
-			if err != nil {
-				return
+			return fs.ImportJSON(data)
+		},
+	}
+
+	var importDryRun bool
+	var importGithubCmd = &cobra.Command{
+		Use:   "github <issues.json>",
+		Short: "Import issues from a GitHub issues export",
+		Long:  "Import issues from the json written by gh api --paginate repos/<owner>/<repo>/issues?state=all. Types come from epic, story and task labels, relationships from sub-issues and from \"blocked by #1\", \"blocks #1\" and \"part of #1\" in issue bodies",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrackerImport(cmd, args[0], importDryRun, importer.ConvertGithub)
+		},
+	}
+
+	var importJiraCmd = &cobra.Command{
+		Use:   "jira <export.csv>",
+		Short: "Import issues from a Jira CSV export",
+		Long:  "Import issues from a Jira CSV export with all fields. Epic links, parents and sub-tasks become hierarchy relationships and Blocks links become dependencies",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrackerImport(cmd, args[0], importDryRun, importer.ConvertJira)
+		},
+	}
+
+	var docRoot string
+	var docCmd = &cobra.Command{
+		Use:   "doc",
+		Short: "Write the requirements as one markdown document",
+		Long:  "Write every issue, or the issues below --root, as one markdown document with numbered sections, a table of contents, dependency tables and a traceability matrix. The output can be converted with pandoc",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
 			}
 
-			if epics > 0 {
-				epicTrie := trie.LoadReconcilableTrie("./.pm/trie/epic")
-				defer epicTrie.SaveReconcilable()
+			document, documentErr := fs.Document(docRoot)
+			if documentErr != nil {
+				return documentErr
+			}
 
-				updateErr := fileManager.UpdateBlobContent(eValues[0], string(content), epicTrie)
-				if updateErr != nil {
-					log.Println("Update file error")
-					return
-				}
-			} else if stories > 0 {
-				storyTrie := trie.LoadReconcilableTrie("./.pm/trie/story")
-				defer storyTrie.SaveReconcilable()
+			fmt.Fprint(cmd.OutOrStdout(), document)
+			return nil
+		},
+	}
 
-				updateErr := fileManager.UpdateBlobContent(sValues[0], string(content), storyTrie)
-				if updateErr != nil {
-					log.Println("Update file error")
-					return
-				}
-			} else if tasks > 0 {
-				taskTrie := trie.LoadReconcilableTrie("./.pm/trie/task")
-				defer taskTrie.SaveReconcilable()
+	var siteTitle string
+	var siteCmd = &cobra.Command{
+		Use:   "site <outdir>",
+		Short: "Render the project as a static HTML site",
+		Long:  "Render every issue as an HTML page linking its parent, children, upstream and downstream issues, with an index page per type and a dependency graph. Existing files in outdir with the same names are overwritten",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs, bootErr := bootFileSystem()
+			if bootErr != nil {
+				return bootErr
+			}
 
-				updateErr := fileManager.UpdateBlobContent(tValues[0], string(content), taskTrie)
-				if updateErr != nil {
-					log.Println("Update file error")
-					return
-				}
+			exported, exportErr := fs.Export()
+			if exportErr != nil {
+				return exportErr
 			}
 
-			err = emptyFile(filePath)
-			if err != nil {
-				log.Println("Cannot empty file")
-				return
+			buildErr := site.Build(exported, siteTitle, args[0])
+			if buildErr != nil {
+				return buildErr
 			}
-			return
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Wrote "+strconv.Itoa(len(exported.Issues))+" issues to "+args[0])
+			return nil
 		},
 	}
 
@@ -607,45 +719,6 @@ func init() {
 		},
 	}
 
-	var epicCmd = &cobra.Command{
-		Use:   "epics",
-		Short: "Epic suggestions",
-		Run: func(cmd *cobra.Command, args []string) {
-			toComplete := args[0]
-			epicTrie := trie.LoadReconcilableTrie("./.pm/trie/epic").DataStructure.(*trie.Trie)
-			if epicTrie != nil {
-				suggestions := epicTrie.LoadWordsFromPrefix(toComplete)
-				log.Println(strings.Join(suggestions, "\n"))
-			}
-		},
-	}
-
-	var storyCmd = &cobra.Command{
-		Use:   "story",
-		Short: "Story suggestions",
-		Run: func(cmd *cobra.Command, args []string) {
-			toComplete := args[0]
-			storyTrie := trie.LoadReconcilableTrie("./.pm/trie/story").DataStructure.(*trie.Trie)
-			if storyTrie != nil {
-				suggestions := storyTrie.LoadWordsFromPrefix(toComplete)
-				log.Println(strings.Join(suggestions, "\n"))
-			}
-		},
-	}
-
-	var taskCmd = &cobra.Command{
-		Use:   "task",
-		Short: "Task suggestions",
-		Run: func(cmd *cobra.Command, args []string) {
-			toComplete := args[0]
-			taskTrie := trie.LoadReconcilableTrie("./.pm/trie/task").DataStructure.(*trie.Trie)
-			if taskTrie != nil {
-				suggestions := taskTrie.LoadWordsFromPrefix(toComplete)
-				log.Println(strings.Join(suggestions, "\n"))
-			}
-		},
-	}
-
 	var pullCmd = &cobra.Command{
 		Use:   "pull",
 		Short: "Pull delta",
@@ -660,118 +733,355 @@ func init() {
 		},
 	}
 
-	var testCmd = &cobra.Command{
-		Use:   "test",
-		Short: "Test",
-		Run: func(cmd *cobra.Command, args []string) {
-		},
-	}
-
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(attachCmd)
 	rootCmd.AddCommand(detachCmd)
+	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(linkCmd)
-	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(unlinkCmd)
 	rootCmd.AddCommand(deleteCmd)
-	rootCmd.AddCommand(epicCmd)
-	rootCmd.AddCommand(storyCmd)
-	rootCmd.AddCommand(taskCmd)
-	rootCmd.AddCommand(viewCmd)
-	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(moveCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(queryCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(nextCmd)
+	rootCmd.AddCommand(impactCmd)
+	rootCmd.AddCommand(criticalPathCmd)
+	rootCmd.AddCommand(estimateCmd)
+	rootCmd.AddCommand(priorityCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importGithubCmd)
+	importCmd.AddCommand(importJiraCmd)
+	rootCmd.AddCommand(siteCmd)
+	rootCmd.AddCommand(docCmd)
 	rootCmd.AddCommand(pullCmd)
 	rootCmd.AddCommand(pushCmd)
 
-	rootCmd.AddCommand(testCmd)
+	createCmd.Flags().StringVarP(&createType, "type", "t", "", "Type of the issue, epic, story or task")
+	createCmd.MarkFlagRequired("type")
+	createCmd.Flags().StringVarP(&createParent, "parent", "p", "", "Parent issue of the new issue")
+	createCmd.Flags().StringVar(&createTitle, "title", "", "Name of the new issue, instead of the argument")
+	createCmd.Flags().StringSliceVar(&createDependsOn, "depends-on", nil, "Issues blocking the new issue, repeat or separate with commas")
+	createCmd.Flags().StringVar(&createBodyFile, "body-file", "", "Read the body from this file, - for stdin")
+	editCmd.Flags().StringVar(&editBodyFile, "body-file", "", "Replace the body with this file, - for stdin")
+
+	linkCmd.Flags().StringVarP(&relationship, "relationship", "r", "hierarchy", "Relationship between the issues, hierarchy or dependency")
+	unlinkCmd.Flags().StringVarP(&relationship, "relationship", "r", "hierarchy", "Relationship between the issues, hierarchy or dependency")
 
-	listCmd.AddCommand(listEpicsCmd)
-	listCmd.AddCommand(listStoriesCmd)
-	listCmd.AddCommand(listTasksCmd)
+	listCmd.Flags().StringVarP(&listType, "type", "t", "", "Only list issues of this type")
+	listCmd.Flags().StringVarP(&listStatus, "status", "s", "", "Only list issues with this status")
 
-	// Add flags to the add command for epic, task, and story
-	linkCmd.Flags().StringSliceVarP(&eValues, "epic", "e", []string{}, "Add an epic")
-	linkCmd.Flags().StringSliceVarP(&sValues, "story", "s", []string{}, "Add a story")
-	linkCmd.Flags().StringSliceVarP(&tValues, "task", "t", []string{}, "Add a task")
+	queryCmd.Flags().StringVarP(&listType, "type", "t", "", "Only find issues of this type")
+	queryCmd.Flags().StringVarP(&listStatus, "status", "s", "", "Only find issues with this status")
+	queryCmd.Flags().StringVarP(&queryIssue.Priority, "priority", "p", "", "Only find issues with this priority")
+	queryCmd.Flags().StringVar(&queryIssue.Under, "under", "", "Only find issues below this issue in the hierarchy")
+	queryCmd.Flags().BoolVar(&queryIssue.Blocked, "blocked", false, "Only find issues waiting on an upstream dependency")
 
-	addCmd.Flags().StringSliceVarP(&eValues, "epic", "e", []string{}, "Add an epic")
-	addCmd.Flags().StringSliceVarP(&sValues, "story", "s", []string{}, "Add a story")
-	addCmd.Flags().StringSliceVarP(&tValues, "task", "t", []string{}, "Add a task")
+	deleteCmd.Flags().BoolVar(&cascade, "cascade", false, "Delete all child issues as well")
+	deleteCmd.Flags().BoolVar(&reparent, "reparent", false, "Move child issues to the parent of the deleted issue")
 
-	deleteCmd.Flags().StringSliceVarP(&eValues, "epic", "e", []string{}, "Delete an epic")
-	deleteCmd.Flags().StringSliceVarP(&sValues, "story", "s", []string{}, "Delete a story")
-	deleteCmd.Flags().StringSliceVarP(&tValues, "task", "t", []string{}, "Delete a task")
+	moveCmd.Flags().StringVar(&moveTo, "to", "", "The new parent of the issue")
+	moveCmd.MarkFlagRequired("to")
 
-	viewCmd.Flags().StringSliceVarP(&eValues, "epic", "e", []string{}, "Add an epic")
-	viewCmd.Flags().StringSliceVarP(&sValues, "story", "s", []string{}, "Add an story")
-	viewCmd.Flags().StringSliceVarP(&tValues, "task", "t", []string{}, "Add a task")
+	nextCmd.Flags().IntVarP(&nextLimit, "limit", "n", 0, "Only list the first n issues")
 
-	editCmd.Flags().StringSliceVarP(&eValues, "epic", "e", []string{}, "Add an epic")
-	editCmd.Flags().StringSliceVarP(&sValues, "story", "s", []string{}, "Add an story")
-	editCmd.Flags().StringSliceVarP(&tValues, "task", "t", []string{}, "Add a task")
+	graphCmd.Flags().StringVar(&graphFormat, "format", fileSystem.GRAPH_FORMAT_DOT, "Output format, dot or mermaid")
+	graphCmd.Flags().StringVar(&graphRoot, "root", "", "Only export the issues reachable from this issue")
+	graphCmd.Flags().StringSliceVar(&graphLabels, "labels", []string{"hierarchy", "dependency"}, "Relationships to follow, hierarchy and/or dependency")
 
+	importCmd.PersistentFlags().BoolVar(&importDryRun, "dry-run", false, "Only report what would be imported")
+	docCmd.Flags().StringVar(&docRoot, "root", "", "Only include this issue and the issues below it")
+	siteCmd.Flags().StringVar(&siteTitle, "title", "Project", "Title shown on every page")
+	exportCmd.Flags().StringVar(&exportFormat, "format", fileSystem.EXPORT_FORMAT_JSON, "Output format, only json is supported")
+
+	for _, readCmd := range []*cobra.Command{listCmd, queryCmd, showCmd, nextCmd, impactCmd, criticalPathCmd, lintCmd, graphCmd, commitsCmd} {
+		readCmd.Flags().StringVarP(&output, "output", "o", OUTPUT_TEXT, "Output format, text, json, yaml or tsv")
+		readCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			return validateOutput(output)
+		}
+	}
+
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configSetCmd.Flags().BoolVar(&configUser, "user", false, "Change the user config instead of the project config")
+	rootCmd.PersistentFlags().StringVar(&project, "project", "", "Directory containing the .pm project, instead of searching the working directory and its parents")
+	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(commitsCmd)
+	scanCmd.Flags().BoolVar(&scanRescan, "rescan", false, "Rebuild the links from the whole history")
+	scanCmd.Flags().BoolVar(&scanClose, "close", false, "Move issues named in Closes: trailers to done")
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookPrepareCommitMsgCmd)
+	hookCmd.AddCommand(hookCommitMsgCmd)
+	hookCmd.AddCommand(hookPostCommitCmd)
+	hooksInstallCmd.Flags().BoolVar(&hooksForce, "force", false, "Replace hooks that pm didn't install")
+	hooksInstallCmd.Flags().BoolVar(&hooksClose, "close", false, "Move issues named in Closes: trailers to done after each commit")
+	hookPostCommitCmd.Flags().BoolVar(&hookClose, "close", false, "Move issues named in Closes: trailers to done")
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceAddCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(workspaceUseCmd)
+	workspaceCmd.AddCommand(workspaceRemoveCmd)
+	rootCmd.PersistentFlags().StringVarP(&workspace, "workspace", "w", "", "Name of the workspace to work on, see pm workspace")
+
+	issues := func() []string { return issueNames() }
+	createCmd.ValidArgsFunction = completeValues()
+	linkCmd.ValidArgsFunction = completeIssueArgs(2)
+	unlinkCmd.ValidArgsFunction = completeIssueArgs(2)
+	showCmd.ValidArgsFunction = completeIssueArgs(1)
+	editCmd.ValidArgsFunction = completeIssueArgs(1)
+	deleteCmd.ValidArgsFunction = completeIssueArgs(1)
+	moveCmd.ValidArgsFunction = completeIssueArgs(1)
+	impactCmd.ValidArgsFunction = completeIssueArgs(1)
+	estimateCmd.ValidArgsFunction = completeIssueArgs(1)
+	priorityCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return pmfile.FILE_PRIORITIES, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return completeIssueArgs(1)(cmd, args, toComplete)
+	}
+	criticalPathCmd.ValidArgsFunction = completeIssueArgs(1, pmfile.FILE_TYPE_EPIC)
+	commitsCmd.ValidArgsFunction = completeIssueArgs(1)
+	configGetCmd.ValidArgsFunction = completeSettingKeys
+	configSetCmd.ValidArgsFunction = completeSettingKeys
+	workspaceUseCmd.ValidArgsFunction = completeWorkspaces
+	workspaceRemoveCmd.ValidArgsFunction = completeWorkspaces
+
+	createCmd.RegisterFlagCompletionFunc("type", completeTypes)
+	createCmd.RegisterFlagCompletionFunc("parent", completeParents(&createType))
+	createCmd.RegisterFlagCompletionFunc("depends-on", completeList(issues))
+	listCmd.RegisterFlagCompletionFunc("type", completeTypes)
+	listCmd.RegisterFlagCompletionFunc("status", completeValues(pmfile.FILE_STATUSES...))
+	queryCmd.RegisterFlagCompletionFunc("type", completeTypes)
+	queryCmd.RegisterFlagCompletionFunc("status", completeValues(pmfile.FILE_STATUSES...))
+	queryCmd.RegisterFlagCompletionFunc("priority", completeValues(pmfile.FILE_PRIORITIES...))
+	queryCmd.RegisterFlagCompletionFunc("under", completeIssueArgs(1))
+	linkCmd.RegisterFlagCompletionFunc("relationship", completeValues(relationshipNames()...))
+	unlinkCmd.RegisterFlagCompletionFunc("relationship", completeValues(relationshipNames()...))
+	moveCmd.RegisterFlagCompletionFunc("to", completeIssueArgs(1))
+	graphCmd.RegisterFlagCompletionFunc("format", completeValues(fileSystem.GRAPH_FORMATS...))
+	graphCmd.RegisterFlagCompletionFunc("root", completeIssueArgs(1))
+	graphCmd.RegisterFlagCompletionFunc("labels", completeList(relationshipNames))
+	docCmd.RegisterFlagCompletionFunc("root", completeIssueArgs(1))
+	exportCmd.RegisterFlagCompletionFunc("format", completeValues(fileSystem.EXPORT_FORMAT_JSON))
+	rootCmd.RegisterFlagCompletionFunc("workspace", completeWorkspaces)
+	for _, readCmd := range []*cobra.Command{listCmd, queryCmd, showCmd, nextCmd, impactCmd, criticalPathCmd, lintCmd, graphCmd, commitsCmd} {
+		readCmd.RegisterFlagCompletionFunc("output", completeValues(OUTPUT_FORMATS...))
+	}
+
+	markUsageErrors(rootCmd)
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
 
 func Execute() {
+	// Until the log file from the settings is opened, logs would mix with the output
+	log.SetOutput(io.Discard)
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// Reads a file, or stdin when the path is -
+func readInput(cmd *cobra.Command, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(cmd.InOrStdin())
 	}
+
+	return os.ReadFile(path)
 }
 
-func buildSection(description []byte, listHeader string) {
-	t := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
-		Headers(listHeader)
+// Body of a new issue, empty without --body-file. Stdin is only read for --body-file -
+// so pm can run inside loops that read their own input from it.
+func readBody(cmd *cobra.Command, path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
 
-	t.Row(string(description))
-	log.Println(t)
+	body, readErr := readInput(cmd, path)
+	return string(body), readErr
 }
 
-func buildList(rows []string, listHeader string) {
-	rowIds := [][]string{}
-	for _, value := range rows {
-		row := []string{value}
-		rowIds = append(rowIds, row)
+func runTrackerImport(cmd *cobra.Command, path string, dryRun bool, convert func([]byte) (importer.Report, error)) (runErr error) {
+	data, readErr := readInput(cmd, path)
+	if readErr != nil {
+		return readErr
+	}
+
+	report, convertErr := convert(data)
+	if convertErr != nil {
+		return convertErr
+	}
+
+	fs, bootErr := bootFileSystem()
+	if bootErr != nil {
+		return bootErr
 	}
 
-	if len(rowIds) == 0 {
-		return
+	// A dry run imports into a temporary copy, the project itself is only read
+	if dryRun {
+		fmt.Fprint(cmd.OutOrStdout(), "Dry run, nothing was imported\n\n"+report.String())
+		return fs.DryRunImport(report.Project)
 	}
+	defer shutDown(fs, &runErr)
 
-	t := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
-		Headers(listHeader).
-		Rows(rowIds...)
+	importErr := fs.Import(report.Project)
+	if importErr != nil {
+		return importErr
+	}
 
-	log.Println(t)
+	fmt.Fprint(cmd.OutOrStdout(), "Imported "+report.String())
+	return nil
 }
 
-func openEditor(editor string, filePath string) error {
-	// Create an exec command to open the file in the editor
-	cmd := exec.Command(editor, filePath)
+// Environment variable naming the .pm directory, like GIT_DIR
+const PM_DIR_ENV = "PM_DIR"
 
-	// Set the command to use the same standard input, output, and error streams as the Go process
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// The .pm directory named by --project, --workspace or $PM_DIR, empty when none is set
+func configuredRoot() (string, error) {
+	if project != "" {
+		return filepath.Join(project, fileSystem.PROJECT_DIRECTORY), nil
+	}
 
-	// Run the command and wait for it to finish
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("failed to open file in editor: %v", err)
+	if workspace != "" {
+		return workspaceRoot(workspace)
 	}
 
-	return nil
+	return os.Getenv(PM_DIR_ENV), nil
+}
+
+// The .pm directory to work on, found in the working directory or one of its parents
+// unless --project, --workspace or $PM_DIR is given. Outside of any project the
+// current workspace is used.
+func projectRoot() (string, error) {
+	root, configuredErr := configuredRoot()
+	if configuredErr != nil {
+		return "", configuredErr
+	}
+
+	if root == "" {
+		workingDir, wdErr := os.Getwd()
+		if wdErr != nil {
+			return "", wdErr
+		}
+
+		found, findErr := fileSystem.FindProjectRoot(workingDir)
+		if findErr == nil {
+			return found, nil
+		}
+
+		workspaces, loadErr := loadWorkspaces()
+		if loadErr != nil || workspaces.Current == "" {
+			return "", findErr
+		}
+
+		root, configuredErr = workspaceRoot(workspaces.Current)
+		if configuredErr != nil {
+			return "", configuredErr
+		}
+	}
+
+	if !fileSystem.IsProjectRoot(root) {
+		return "", fileSystem.NotFoundError("No pm project found in " + root + ", run pm init to create one")
+	}
+
+	return root, nil
+}
+
+func bootFileSystem() (*fileSystem.FileSystem, error) {
+	root, rootErr := projectRoot()
+	if rootErr != nil {
+		return nil, rootErr
+	}
+
+	return bootFileSystemAt(root)
+}
+
+func bootFileSystemAt(root string) (*fileSystem.FileSystem, error) {
+	fs := fileSystem.NewFileSystem(root)
+	if settings != nil {
+		fs.SetEditor(settings.GetString(config.SETTING_EDITOR))
+
+		commitErr := fs.SetAutoCommit(settings.GetString(config.SETTING_GIT_AUTOCOMMIT))
+		if commitErr != nil {
+			return nil, commitErr
+		}
+	}
+
+	bootErr := fs.Boot()
+	if bootErr != nil {
+		return nil, bootErr
+	}
+
+	return fs, nil
+}
+
+// Saves the project once a command is done, deferred so a failed save or commit
+// is returned unless the command already failed
+func shutDown(fs *fileSystem.FileSystem, runErr *error) {
+	shutDownErr := fs.ShutDown()
+	if *runErr == nil {
+		*runErr = shutDownErr
+	}
 }
 
-func emptyFile(filePath string) error {
-	// Truncate the file to zero length
-	err := os.Truncate(filePath, 0)
+// Opens the TUI when pm is run without a sub command, and reopens it in the workspace
+// picked in its workspace switcher
+func runApplication() error {
+	for {
+		next, runErr := runApplicationOnce()
+		if runErr != nil || next == "" {
+			return runErr
+		}
+
+		useErr := useWorkspace(next)
+		if useErr != nil {
+			return useErr
+		}
+
+		project, workspace = "", next
+		settingsErr := applySettings()
+		if settingsErr != nil {
+			return settingsErr
+		}
+	}
+}
+
+// Runs the TUI until it quits, returns the workspace picked in the switcher
+func runApplicationOnce() (workspace string, runErr error) {
+	fs, bootErr := bootFileSystem()
+	if bootErr != nil {
+		return "", bootErr
+	}
+	defer shutDown(fs, &runErr)
+
+	// Keeps the commits shown with each issue current, projects outside of git have none
+	_, scanErr := fs.ScanCommits(false, false)
+	if scanErr != nil {
+		log.Println("Error scanning commits", scanErr)
+	}
+
+	app, appErr := application.NewApplication(fs, settings.KeyBindings())
+	if appErr != nil {
+		return "", appErr
+	}
+
+	final, err := tea.NewProgram(app).Run()
 	if err != nil {
-		return fmt.Errorf("failed to truncate the file: %v", err)
+		log.Println("Error running program:", err)
+		return "", err
 	}
-	return nil
+
+	return final.(application.Application).Workspace, nil
 }
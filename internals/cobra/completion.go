@@ -0,0 +1,157 @@
+package cobra
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github/pm/pkg/config"
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+)
+
+/**
+Dynamic shell completion. Issue names are read from the file type index of the
+project pm would work on, pm completion <shell> prints the script.
+*/
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+var completionCmd = &cobra.Command{
+	Use:       "completion <bash|zsh|fish>",
+	Short:     "Print the shell completion script",
+	Long:      "Print the shell completion script. Load it with\n\n  bash: source <(pm completion bash)\n  zsh:  pm completion zsh > \"${fpath[1]}/_pm\"\n  fish: pm completion fish > ~/.config/fish/completions/pm.fish",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return cmd.Root().GenBashCompletionV2(out, true)
+		case "zsh":
+			return cmd.Root().GenZshCompletion(out)
+		case "fish":
+			return cmd.Root().GenFishCompletion(out, true)
+		}
+
+		return fileSystem.InvalidError("Unknown shell " + args[0] + ", expected bash, zsh or fish")
+	},
+}
+
+// Names of the issues with one of fileTypes, or every issue when none are given.
+func issueNames(fileTypes ...string) []string {
+	completionSettings()
+	fs, bootErr := bootFileSystem()
+	if bootErr != nil {
+		return nil
+	}
+
+	files, filesErr := fs.ListAllFilesWithTypes()
+	if filesErr != nil {
+		return nil
+	}
+
+	var names []string
+	for fileType, fileNames := range files {
+		if len(fileTypes) == 0 || contains(fileTypes, fileType) {
+			names = append(names, fileNames...)
+		}
+	}
+
+	names, _ = sortedNames(names, nil)
+	return names
+}
+
+// Completes issue names for the first count arguments, skipping issues already given
+func completeIssueArgs(count int, fileTypes ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= count {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var names []string
+		for _, name := range issueNames(fileTypes...) {
+			if !contains(args, name) {
+				names = append(names, name)
+			}
+		}
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeValues(values ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// Comma separated flags complete the next value after the ones already typed
+func completeList(values func() []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		typed := ""
+		if index := strings.LastIndex(toComplete, ","); index != -1 {
+			typed = toComplete[:index+1]
+		}
+
+		var completions []string
+		for _, value := range values() {
+			if !contains(strings.Split(typed, ","), value) {
+				completions = append(completions, typed+value)
+			}
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+func relationshipNames() []string {
+	var names []string
+	for _, label := range fileSystem.FILE_RELATIONSHIPS {
+		names = append(names, strings.ToLower(label))
+	}
+
+	return names
+}
+
+// Parents that are valid for the type given with --type
+func completeParents(fileType *string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completionSettings()
+		parentTypes := pmfile.FILE_TYPE_HIERARCHY
+		if contains(pmfile.FILE_TYPE_HIERARCHY, *fileType) {
+			parentTypes = fileSystem.ValidParentTypes(*fileType)
+		}
+
+		if len(parentTypes) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return issueNames(parentTypes...), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// Completion runs without the pre run hooks, the settings are applied on first use
+func completionSettings() {
+	if settings == nil {
+		applySettings()
+	}
+}
+
+func completeTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completionSettings()
+	return pmfile.FILE_TYPE_HIERARCHY, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var keys []string
+	for _, setting := range config.SETTINGS {
+		keys = append(keys, setting.Key+"\t"+setting.Description)
+	}
+
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
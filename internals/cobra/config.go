@@ -0,0 +1,228 @@
+package cobra
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github/pm/pkg/config"
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+)
+
+// Loaded before every command by applySettings
+var settings *config.Config
+
+var configUser bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change settings",
+	Long:  "Read and change settings. The project config in .pm/config.toml overrides the user config in ~/.config/pm/config.toml",
+	// Settings are not applied, a broken config can still be inspected and fixed
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyErr := config.ValidateKey(args[0])
+		if keyErr != nil {
+			return fileSystem.InvalidError(keyErr.Error())
+		}
+
+		loaded, loadErr := loadSettings()
+		if loadErr != nil {
+			return loadErr
+		}
+
+		value, _, ok := loaded.Get(args[0])
+		if !ok {
+			return fileSystem.NotFoundError(args[0] + " is not set")
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), config.FormatValue(value))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the project config, or the user config with --user",
+	Long:  "Change a setting in the project config, or the user config with --user. Lists such as issue.types are given comma separated",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, valueErr := config.ParseValue(args[0], args[1])
+		if valueErr != nil {
+			return fileSystem.InvalidError(valueErr.Error())
+		}
+
+		if args[0] == config.SETTING_ISSUE_TYPES {
+			fileTypes := strings.Split(args[1], ",")
+			typesErr := pmfile.SetFileTypes(fileTypes)
+			if typesErr != nil {
+				return fileSystem.InvalidError(typesErr.Error())
+			}
+
+			inUseErr := validateTypesInUse(fileTypes)
+			if inUseErr != nil {
+				return inUseErr
+			}
+		}
+
+		if args[0] == config.SETTING_GIT_AUTOCOMMIT {
+			modeErr := fileSystem.ValidateAutoCommitMode(args[1])
+			if modeErr != nil {
+				return modeErr
+			}
+		}
+
+		path, pathErr := settingsPath(configUser)
+		if pathErr != nil {
+			return pathErr
+		}
+
+		return config.Set(path, args[0], value)
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every setting with its value and where it was set",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loaded, loadErr := loadSettings()
+		if loadErr != nil {
+			return loadErr
+		}
+
+		for _, entry := range loaded.List() {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s = %s  # %s\n", entry.Key, config.FormatToml(entry.Value), entry.Source)
+		}
+
+		return nil
+	},
+}
+
+// Refuses issue types that would leave issues of the current project without their type
+func validateTypesInUse(fileTypes []string) error {
+	if _, rootErr := projectRoot(); rootErr != nil {
+		return nil
+	}
+
+	fs, bootErr := bootFileSystem()
+	if bootErr != nil {
+		return bootErr
+	}
+
+	files, filesErr := fs.ListAllFilesWithTypes()
+	if filesErr != nil {
+		return filesErr
+	}
+
+	for _, fileType := range slices.Sorted(maps.Keys(files)) {
+		if len(files[fileType]) > 0 && !slices.Contains(fileTypes, fileType) {
+			return fileSystem.ConflictError("Issue type " + fileType + " is still used by " + strings.Join(files[fileType], ", ") + ", move or delete them first")
+		}
+	}
+
+	return nil
+}
+
+// The user config, and the config of the project when there is one
+func loadSettings() (*config.Config, error) {
+	userPath, userErr := config.UserPath()
+	if userErr != nil {
+		userPath = ""
+	}
+
+	projectPath := ""
+	if root, rootErr := projectRoot(); rootErr == nil {
+		projectPath = config.ProjectPath(root)
+	}
+
+	loaded, loadErr := config.Load(userPath, projectPath)
+	if loadErr != nil {
+		return nil, fileSystem.InvalidError(loadErr.Error())
+	}
+
+	return loaded, nil
+}
+
+func settingsPath(user bool) (string, error) {
+	if user {
+		return config.UserPath()
+	}
+
+	root, rootErr := projectRoot()
+	if rootErr != nil {
+		return "", rootErr
+	}
+
+	return config.ProjectPath(root), nil
+}
+
+// Loads the settings and applies the ones that are global to the process
+func applySettings() error {
+	loaded, loadErr := loadSettings()
+	if loadErr != nil {
+		return loadErr
+	}
+
+	settings = loaded
+
+	loggerErr := setupLogger(settings.GetString(config.SETTING_LOG_FILE))
+	if loggerErr != nil {
+		return loggerErr
+	}
+
+	typesErr := pmfile.SetFileTypes(settings.GetStrings(config.SETTING_ISSUE_TYPES))
+	if typesErr != nil {
+		return fileSystem.InvalidError(config.SETTING_ISSUE_TYPES + ": " + typesErr.Error())
+	}
+
+	return nil
+}
+
+// Appends the log to path, relative paths start in the working directory and ~/ in the home directory
+func setupLogger(path string) error {
+	if path == "" {
+		log.SetOutput(io.Discard)
+		return nil
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		home, homeErr := os.UserHomeDir()
+		if homeErr != nil {
+			return homeErr
+		}
+
+		path = filepath.Join(home, path[2:])
+	}
+
+	mkdirErr := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if mkdirErr != nil {
+		return mkdirErr
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	log.SetOutput(file)
+	log.SetPrefix("LOG: ")
+	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+	return nil
+}
@@ -0,0 +1,35 @@
+package cobra
+
+import (
+	"errors"
+	"testing"
+
+	"github/pm/pkg/config"
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+)
+
+func TestConfigSetRefusesIssueTypesInUse(t *testing.T) {
+	bootRepository(t)
+	t.Cleanup(func() { pmfile.SetFileTypes([]string{"epic", "story", "task"}) })
+
+	mustRun(t, "init")
+	mustRun(t, "create", "Auth", "--type", "story")
+
+	setErr := runPm(t, "config", "set", config.SETTING_ISSUE_TYPES, "epic,task")
+	if !errors.Is(setErr, fileSystem.ErrConflict) {
+		t.Fatalf("expected dropping story to conflict, got %v", setErr)
+	}
+
+	loaded, loadErr := loadSettings()
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	if _, source, _ := loaded.Get(config.SETTING_ISSUE_TYPES); source != config.SOURCE_DEFAULT {
+		t.Errorf("refused issue types were written to the %s config", source)
+	}
+
+	// Types without issues can be dropped
+	mustRun(t, "config", "set", config.SETTING_ISSUE_TYPES, "story,task,bug")
+}
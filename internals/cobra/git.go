@@ -0,0 +1,78 @@
+package cobra
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var scanRescan, scanClose bool
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Link commits to the issues their messages refer to",
+	Long:  "Read the commits added to local branches since the last scan and link them to the issues named in Refs: and Closes: trailers, by name or by the key an issue was imported from such as PM-12. With --close the issues named in Closes: trailers are moved to done",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+		fs, bootErr := bootFileSystem()
+		if bootErr != nil {
+			return bootErr
+		}
+		defer shutDown(fs, &runErr)
+
+		report, scanErr := fs.ScanCommits(scanRescan, scanClose)
+		if scanErr != nil {
+			return scanErr
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintln(out, "Scanned "+strconv.Itoa(report.Commits)+" commits, "+strconv.Itoa(report.Links)+" new links")
+		if len(report.Closed) > 0 {
+			fmt.Fprintln(out, "Closed "+strings.Join(report.Closed, ", "))
+		}
+
+		if len(report.Unknown) > 0 {
+			fmt.Fprintln(out, "No issue found for "+strings.Join(report.Unknown, ", "))
+		}
+
+		return nil
+	},
+}
+
+var commitsCmd = &cobra.Command{
+	Use:   "commits <issue>",
+	Short: "List the commits linked to an issue by pm scan",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fs, bootErr := bootFileSystem()
+		if bootErr != nil {
+			return bootErr
+		}
+
+		linked, linkedErr := fs.LinkedCommits(args[0])
+		if linkedErr != nil {
+			return linkedErr
+		}
+
+		commits := []linkedCommit{}
+		rows := [][]string{{"hash", "date", "author", "closes", "subject"}}
+		for _, commit := range linked {
+			commits = append(commits, linkedCommit{commit.Hash, commit.Date, commit.Author, commit.Closes, commit.Subject})
+			rows = append(rows, []string{commit.Hash, commit.Date, commit.Author, strconv.FormatBool(commit.Closes), commit.Subject})
+		}
+
+		return writeOutput(cmd.OutOrStdout(), output, commits, rows, func(out io.Writer) {
+			for _, commit := range linked {
+				closes := ""
+				if commit.Closes {
+					closes = " (closes)"
+				}
+
+				fmt.Fprintf(out, "%.7s %.10s %s%s\n", commit.Hash, commit.Date, commit.Subject, closes)
+			}
+		})
+	},
+}
@@ -0,0 +1,211 @@
+package cobra
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github/pm/pkg/fileSystem"
+	"github/pm/pkg/git"
+)
+
+/**
+Git hooks. The installed scripts only run pm hook <name>, so what they do lives here.
+*/
+
+var hooksForce, hooksClose, hookClose bool
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install git hooks that link commits to issues",
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the prepare-commit-msg, commit-msg and post-commit hooks",
+	Long:  "Install git hooks into the repository of the project. prepare-commit-msg adds Refs: with the issue being worked on, commit-msg rejects references to issues that don't exist and post-commit links the commit to its issues, with --close moving the issues it closes to done",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, rootErr := projectRoot()
+		if rootErr != nil {
+			return rootErr
+		}
+
+		root, absErr := filepath.Abs(root)
+		if absErr != nil {
+			return absErr
+		}
+
+		// Hooks run from the top of the work tree, the project is passed along
+		executable, executableErr := os.Executable()
+		if executableErr != nil {
+			executable = "pm"
+		}
+
+		command := "env " + PM_DIR_ENV + "=" + git.ShellQuote(root) + " " + git.ShellQuote(executable)
+		scripts := map[string]string{}
+		for _, name := range git.HOOKS {
+			var flags []string
+			if name == git.HOOK_POST_COMMIT && hooksClose {
+				flags = append(flags, "--close")
+			}
+
+			scripts[name] = git.HookScript(command, name, flags...)
+		}
+
+		hooks, installErr := git.InstallHooks(root, scripts, hooksForce)
+		if errors.Is(installErr, git.ErrForeignHook) {
+			return fileSystem.ConflictError(installErr.Error())
+		}
+
+		if installErr != nil {
+			return installErr
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), "Installed "+strings.Join(git.HOOKS, ", ")+" in "+hooks)
+		return nil
+	},
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the hooks installed by pm hooks install",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, rootErr := projectRoot()
+		if rootErr != nil {
+			return rootErr
+		}
+
+		removed, uninstallErr := git.UninstallHooks(root, git.HOOKS)
+		if uninstallErr != nil {
+			return uninstallErr
+		}
+
+		if len(removed) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No hooks installed by pm")
+			return nil
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), "Removed "+strings.Join(removed, ", "))
+		return nil
+	},
+}
+
+var hookCmd = &cobra.Command{
+	Use:    "hook",
+	Short:  "Run a git hook, called by the scripts pm hooks install writes",
+	Hidden: true,
+}
+
+var hookPrepareCommitMsgCmd = &cobra.Command{
+	Use:   git.HOOK_PREPARE_COMMIT_MSG + " <message file> [source] [commit]",
+	Short: "Add Refs: with the issue being worked on to a new commit message",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Messages given with -m, merges, squashes and amends are left alone
+		if len(args) > 1 && args[1] != "" && args[1] != "template" {
+			return nil
+		}
+
+		fs := hookFileSystem(cmd)
+		if fs == nil {
+			return nil
+		}
+
+		trailers, trailersErr := git.MessageTrailers(fs.Root(), args[0])
+		if trailersErr != nil {
+			return trailersErr
+		}
+
+		if len(trailers) > 0 {
+			return nil
+		}
+
+		branch, _ := git.CurrentBranch(fs.Root())
+		issue, ok := fs.CurrentIssue(branch)
+		if !ok {
+			return nil
+		}
+
+		message, readErr := os.ReadFile(args[0])
+		if readErr != nil {
+			return readErr
+		}
+
+		return os.WriteFile(args[0], []byte(git.PrefillTrailer(string(message), "Refs", issue)), 0644)
+	},
+}
+
+var hookCommitMsgCmd = &cobra.Command{
+	Use:   git.HOOK_COMMIT_MSG + " <message file>",
+	Short: "Reject commit messages referring to issues that don't exist",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fs := hookFileSystem(cmd)
+		if fs == nil {
+			return nil
+		}
+
+		trailers, trailersErr := git.MessageTrailers(fs.Root(), args[0])
+		if trailersErr != nil {
+			return trailersErr
+		}
+
+		// Fixes: and Resolves: lines that name no issue are about something else
+		var unknown []string
+		for _, trailer := range trailers {
+			if trailer.Shared {
+				continue
+			}
+
+			_, missing := fs.ResolveTrailer(trailer.Value)
+			unknown = append(unknown, missing...)
+		}
+
+		if len(unknown) > 0 {
+			return fileSystem.NotFoundError("No issue found for " + strings.Join(unknown, ", ") + ", fix the Refs: or Closes: lines or create the issues")
+		}
+
+		return nil
+	},
+}
+
+var hookPostCommitCmd = &cobra.Command{
+	Use:   git.HOOK_POST_COMMIT,
+	Short: "Link the new commit to the issues it refers to",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+		fs := hookFileSystem(cmd)
+		if fs == nil {
+			return nil
+		}
+		defer shutDown(fs, &runErr)
+
+		report, scanErr := fs.ScanCommits(false, hookClose)
+		if scanErr != nil {
+			return scanErr
+		}
+
+		if len(report.Closed) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "pm: closed "+strings.Join(report.Closed, ", "))
+		}
+
+		return nil
+	},
+}
+
+// Hooks stay out of the way of commits once the project is gone, they only warn
+func hookFileSystem(cmd *cobra.Command) *fileSystem.FileSystem {
+	fs, bootErr := bootFileSystem()
+	if bootErr != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "pm: skipping "+cmd.Name()+" hook, "+bootErr.Error())
+		return nil
+	}
+
+	return fs
+}
@@ -0,0 +1,133 @@
+package cobra
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+	"github/pm/pkg/git"
+)
+
+// Runs pm with args in the working directory
+func runPm(t *testing.T, args ...string) error {
+	t.Helper()
+
+	var out bytes.Buffer
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	return rootCmd.Execute()
+}
+
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+
+	out, gitErr := exec.Command("git", args...).CombinedOutput()
+	if gitErr != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), out)
+	}
+}
+
+// Changes into a new git repository and returns its path
+func bootRepository(t *testing.T) string {
+	t.Helper()
+
+	workingDir, wdErr := os.Getwd()
+	if wdErr != nil {
+		t.Fatal(wdErr)
+	}
+
+	repo := t.TempDir()
+	if chdirErr := os.Chdir(repo); chdirErr != nil {
+		t.Fatal(chdirErr)
+	}
+	t.Cleanup(func() { os.Chdir(workingDir) })
+
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(repo, "config"))
+	t.Setenv("GIT_AUTHOR_NAME", "pm")
+	t.Setenv("GIT_AUTHOR_EMAIL", "pm@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "pm")
+	t.Setenv("GIT_COMMITTER_EMAIL", "pm@example.com")
+
+	runGit(t, "init", "--quiet")
+	return repo
+}
+
+func TestHooksInTemporaryRepository(t *testing.T) {
+	repo := bootRepository(t)
+	runGit(t, "checkout", "--quiet", "-b", "feature/login-form")
+	for _, args := range [][]string{{"init"}, {"create", "Login form", "--type", "task"}, {"hooks", "install"}} {
+		if runErr := runPm(t, args...); runErr != nil {
+			t.Fatal(runErr)
+		}
+	}
+
+	for _, name := range git.HOOKS {
+		script, readErr := os.ReadFile(filepath.Join(repo, ".git", "hooks", name))
+		if readErr != nil || !strings.Contains(string(script), git.HOOK_MARKER) {
+			t.Errorf("%s hook was not installed: %v", name, readErr)
+		}
+	}
+
+	// The branch names the issue being worked on
+	messageFile := filepath.Join(repo, "COMMIT_EDITMSG")
+	os.WriteFile(messageFile, []byte("\n# Please enter the commit message\n"), 0644)
+	mustRun(t, "hook", git.HOOK_PREPARE_COMMIT_MSG, messageFile)
+	if message, _ := os.ReadFile(messageFile); !strings.Contains(string(message), "\nRefs: Login form\n") {
+		t.Errorf("prepare-commit-msg wrote %q", message)
+	}
+
+	os.WriteFile(messageFile, []byte("Add the form\n\nRefs: Logout\n"), 0644)
+	if hookErr := runPm(t, "hook", git.HOOK_COMMIT_MSG, messageFile); !errors.Is(hookErr, fileSystem.ErrNotFound) {
+		t.Errorf("commit-msg accepted a missing issue: %v", hookErr)
+	}
+
+	// git commit -m "Tidy readme" -m "Fixes: the typo reported on the mailing list"
+	os.WriteFile(messageFile, []byte("Tidy readme\n\nFixes: the typo reported on the mailing list\n"), 0644)
+	mustRun(t, "hook", git.HOOK_COMMIT_MSG, messageFile)
+
+	os.WriteFile(messageFile, []byte("Tidy readme\n\nRefs: Logout is next\nonce the form is in.\n"), 0644)
+	mustRun(t, "hook", git.HOOK_COMMIT_MSG, messageFile)
+
+	os.WriteFile(messageFile, []byte("Add the form\n\nCloses: login form\n"), 0644)
+	mustRun(t, "hook", git.HOOK_COMMIT_MSG, messageFile)
+
+	// The installed hooks would run the test binary, so git commits without them
+	mustRun(t, "hooks", "uninstall")
+	for _, name := range git.HOOKS {
+		if _, statErr := os.Stat(filepath.Join(repo, ".git", "hooks", name)); statErr == nil {
+			t.Errorf("%s hook was not removed", name)
+		}
+	}
+
+	runGit(t, "commit", "--quiet", "--allow-empty", "--file", messageFile)
+	mustRun(t, "hook", git.HOOK_POST_COMMIT, "--close")
+
+	fs := fileSystem.NewFileSystem(filepath.Join(repo, fileSystem.PROJECT_DIRECTORY))
+	if bootErr := fs.Boot(); bootErr != nil {
+		t.Fatal(bootErr)
+	}
+
+	linked, linkedErr := fs.LinkedCommits("Login form")
+	if linkedErr != nil || len(linked) != 1 || !linked[0].Closes || linked[0].Subject != "Add the form" {
+		t.Errorf("post-commit linked %+v, %v", linked, linkedErr)
+	}
+
+	if status := fs.GetFileStatus("Login form"); status != pmfile.FILE_STATUS_DONE {
+		t.Errorf("Login form is %s, want done", status)
+	}
+}
+
+func mustRun(t *testing.T, args ...string) {
+	t.Helper()
+
+	if runErr := runPm(t, args...); runErr != nil {
+		t.Fatal(runErr)
+	}
+}
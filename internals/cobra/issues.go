@@ -0,0 +1,291 @@
+package cobra
+
+import (
+	"sort"
+	"strings"
+
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+)
+
+/**
+Records printed by the read commands. The json field names are the documented
+output schema (docs/cli.md), renaming one breaks scripts.
+*/
+
+type listedIssue struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Status   string   `json:"status"`
+	Priority string   `json:"priority"`
+	Estimate *float64 `json:"estimate"`
+}
+
+type shownIssue struct {
+	listedIssue
+	Parents   []string `json:"parents"`
+	Children  []string `json:"children"`
+	BlockedBy []string `json:"blocked_by"`
+	Blocks    []string `json:"blocks"`
+	Body      string   `json:"body"`
+}
+
+type readyIssue struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Priority string `json:"priority"`
+	Depth    int    `json:"depth"`
+}
+
+type impactReport struct {
+	Issue     string   `json:"issue"`
+	Blocks    []string `json:"blocks"`
+	BlockedBy []string `json:"blocked_by"`
+}
+
+type pathStep struct {
+	Name     string  `json:"name"`
+	Estimate float64 `json:"estimate"`
+}
+
+type criticalPathReport struct {
+	Path  []pathStep `json:"path"`
+	Total float64    `json:"total"`
+}
+
+type linkedCommit struct {
+	Hash    string `json:"hash"`
+	Date    string `json:"date"`
+	Author  string `json:"author"`
+	Closes  bool   `json:"closes"`
+	Subject string `json:"subject"`
+}
+
+type lintedContradiction struct {
+	Issues []string `json:"issues"`
+	Steps  []string `json:"steps"`
+}
+
+type graphNode struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+type graphEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"`
+}
+
+type graphReport struct {
+	Nodes []graphNode `json:"nodes"`
+	Edges []graphEdge `json:"edges"`
+}
+
+func describeIssue(fs *fileSystem.FileSystem, fileName string, fileType string) listedIssue {
+	issue := listedIssue{
+		Name:     fileName,
+		Type:     fileType,
+		Status:   fs.GetFileStatus(fileName),
+		Priority: fs.GetFilePriority(fileName),
+	}
+
+	if _, ok := fs.GetFileMeta(fileName, pmfile.FILE_META_ESTIMATE); ok {
+		estimate := fs.GetFileEstimate(fileName)
+		issue.Estimate = &estimate
+	}
+
+	return issue
+}
+
+// Issues sorted by name, fileType and status filter when not empty
+func listIssues(fs *fileSystem.FileSystem, fileType string, status string) ([]listedIssue, error) {
+	if fileType != "" && !contains(pmfile.FILE_TYPE_HIERARCHY, fileType) {
+		return nil, fileSystem.InvalidError("Unknown type " + fileType + ", expected one of " + strings.Join(pmfile.FILE_TYPE_HIERARCHY, ", "))
+	}
+
+	if status != "" && !contains(pmfile.FILE_STATUSES, status) {
+		return nil, fileSystem.InvalidError("Unknown status " + status + ", expected one of " + strings.Join(pmfile.FILE_STATUSES, ", "))
+	}
+
+	files, filesErr := fs.ListAllFilesWithTypes()
+	if filesErr != nil {
+		return nil, filesErr
+	}
+
+	issues := []listedIssue{}
+	for issueType, fileNames := range files {
+		if fileType != "" && issueType != fileType {
+			continue
+		}
+
+		for _, fileName := range fileNames {
+			issue := describeIssue(fs, fileName, issueType)
+			if status != "" && issue.Status != status {
+				continue
+			}
+
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Name < issues[j].Name
+	})
+
+	return issues, nil
+}
+
+// Narrows listed issues down for pm query
+type issueQuery struct {
+	Text     string // Case insensitive, matched against the name and the body
+	Priority string
+	Under    string // Only issues below this issue in the hierarchy
+	Blocked  bool   // Only issues waiting on an upstream dependency
+}
+
+func queryIssues(fs *fileSystem.FileSystem, fileType string, status string, query issueQuery) ([]listedIssue, error) {
+	if query.Priority != "" && !contains(pmfile.FILE_PRIORITIES, query.Priority) {
+		return nil, fileSystem.InvalidError("Unknown priority " + query.Priority + ", expected one of " + strings.Join(pmfile.FILE_PRIORITIES, ", "))
+	}
+
+	var subtree map[string]bool
+	if query.Under != "" {
+		var subtreeErr error
+		subtree, subtreeErr = fs.HierarchySubtree(query.Under)
+		if subtreeErr != nil {
+			return nil, subtreeErr
+		}
+	}
+
+	issues, listErr := listIssues(fs, fileType, status)
+	if listErr != nil {
+		return nil, listErr
+	}
+
+	text := strings.ToLower(query.Text)
+	matched := []listedIssue{}
+	for _, issue := range issues {
+		if query.Priority != "" && issue.Priority != query.Priority {
+			continue
+		}
+
+		if subtree != nil && (!subtree[issue.Name] || issue.Name == query.Under) {
+			continue
+		}
+
+		if query.Blocked && !fs.IsBlocked(issue.Name) {
+			continue
+		}
+
+		if text != "" && !strings.Contains(strings.ToLower(issue.Name), text) {
+			body, bodyErr := fs.RetrieveFileContents(issue.Name)
+			if bodyErr != nil {
+				return nil, bodyErr
+			}
+
+			if !strings.Contains(strings.ToLower(body), text) {
+				continue
+			}
+		}
+
+		matched = append(matched, issue)
+	}
+
+	return matched, nil
+}
+
+// Sorted and never nil so empty lists are printed as [] instead of null
+func sortedNames(fileNames []string, listErr error) ([]string, error) {
+	if listErr != nil {
+		return nil, listErr
+	}
+
+	sorted := append([]string{}, fileNames...)
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+func showIssue(fs *fileSystem.FileSystem, fileName string) (shownIssue, error) {
+	fileType, typeErr := fs.GetFileType(fileName)
+	if typeErr != nil {
+		return shownIssue{}, typeErr
+	}
+
+	body, bodyErr := fs.RetrieveFileContents(fileName)
+	if bodyErr != nil {
+		return shownIssue{}, bodyErr
+	}
+
+	issue := shownIssue{
+		listedIssue: describeIssue(fs, fileName, fileType),
+		Body:        body,
+	}
+
+	var listErr error
+	if issue.Parents, listErr = sortedNames(fs.ListRelatedParents(fileName, fileSystem.FILE_RELATIONSHIPS_HIERARCHY)); listErr != nil {
+		return shownIssue{}, listErr
+	}
+
+	if issue.Children, listErr = sortedNames(fs.ListRelatedHierarchy(fileName)); listErr != nil {
+		return shownIssue{}, listErr
+	}
+
+	if issue.BlockedBy, listErr = sortedNames(fs.ListRelatedParentDependency(fileName)); listErr != nil {
+		return shownIssue{}, listErr
+	}
+
+	if issue.Blocks, listErr = sortedNames(fs.ListRelatedDependency(fileName)); listErr != nil {
+		return shownIssue{}, listErr
+	}
+
+	return issue, nil
+}
+
+func issueGraph(fs *fileSystem.FileSystem, root string, labels []string) (graphReport, error) {
+	for _, label := range labels {
+		if !contains(fileSystem.FILE_RELATIONSHIPS, label) {
+			return graphReport{}, fileSystem.InvalidError("Unknown relationship " + label)
+		}
+	}
+
+	nodes, edges, graphErr := fs.Subgraph(root, labels)
+	if graphErr != nil {
+		return graphReport{}, graphErr
+	}
+
+	report := graphReport{Nodes: []graphNode{}, Edges: []graphEdge{}}
+	for _, node := range nodes {
+		fileType, typeErr := fs.GetFileType(node)
+		if typeErr != nil {
+			return graphReport{}, typeErr
+		}
+
+		report.Nodes = append(report.Nodes, graphNode{Name: node, Type: fileType, Status: fs.GetFileStatus(node)})
+	}
+
+	for _, edge := range edges {
+		report.Edges = append(report.Edges, graphEdge{From: edge.From, To: edge.To, Label: strings.ToLower(edge.Label)})
+	}
+
+	return report, nil
+}
+
+func formatEstimate(estimate *float64) string {
+	if estimate == nil {
+		return "-"
+	}
+
+	return formatFloat(*estimate)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,262 @@
+package cobra
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github/pm/pkg/fileSystem"
+)
+
+const OUTPUT_TEXT = "text"
+const OUTPUT_JSON = "json"
+const OUTPUT_YAML = "yaml"
+const OUTPUT_TSV = "tsv"
+
+var OUTPUT_FORMATS = []string{OUTPUT_TEXT, OUTPUT_JSON, OUTPUT_YAML, OUTPUT_TSV}
+
+// Exit codes, documented in docs/cli.md
+const EXIT_ERROR = 1
+const EXIT_INVALID = 2
+const EXIT_NOT_FOUND = 3
+const EXIT_CONFLICT = 4
+
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, fileSystem.ErrInvalid):
+		return EXIT_INVALID
+	case errors.Is(err, fileSystem.ErrNotFound):
+		return EXIT_NOT_FOUND
+	case errors.Is(err, fileSystem.ErrConflict):
+		return EXIT_CONFLICT
+	}
+
+	return EXIT_ERROR
+}
+
+// Wraps argument and flag errors of every command so they exit as invalid input
+func markUsageErrors(cmd *cobra.Command) {
+	if cmd.Args != nil {
+		args := cmd.Args
+		cmd.Args = func(cmd *cobra.Command, values []string) error {
+			argsErr := args(cmd, values)
+			if argsErr != nil {
+				return fileSystem.InvalidError(argsErr.Error())
+			}
+
+			return nil
+		}
+	}
+
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, flagErr error) error {
+		return fileSystem.InvalidError(flagErr.Error())
+	})
+
+	for _, child := range cmd.Commands() {
+		markUsageErrors(child)
+	}
+}
+
+func validateOutput(format string) error {
+	if !contains(OUTPUT_FORMATS, format) {
+		return fileSystem.InvalidError("Unknown output format " + format + ", expected one of " + strings.Join(OUTPUT_FORMATS, ", "))
+	}
+
+	return nil
+}
+
+// Prints value in the requested format. rows are used for tsv and text prints the human readable form.
+func writeOutput(out io.Writer, format string, value any, rows [][]string, text func(io.Writer)) error {
+	switch format {
+	case OUTPUT_TEXT:
+		text(out)
+		return nil
+	case OUTPUT_TSV:
+		for _, row := range rows {
+			escaped := make([]string, len(row))
+			for index, cell := range row {
+				escaped[index] = tsvEscape(cell)
+			}
+
+			_, writeErr := io.WriteString(out, strings.Join(escaped, "\t")+"\n")
+			if writeErr != nil {
+				return writeErr
+			}
+		}
+
+		return nil
+	}
+
+	data, marshalErr := json.MarshalIndent(value, "", "  ")
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	if format == OUTPUT_YAML {
+		yaml, yamlErr := jsonToYaml(data)
+		if yamlErr != nil {
+			return yamlErr
+		}
+
+		_, writeErr := io.WriteString(out, yaml)
+		return writeErr
+	}
+
+	_, writeErr := out.Write(append(data, '\n'))
+	return writeErr
+}
+
+// Prints the issues of pm list and pm query
+func writeIssueList(out io.Writer, issues []listedIssue) error {
+	rows := [][]string{{"name", "type", "status", "priority", "estimate"}}
+	for _, issue := range issues {
+		rows = append(rows, []string{issue.Name, issue.Type, issue.Status, issue.Priority, formatEstimate(issue.Estimate)})
+	}
+
+	return writeOutput(out, output, issues, rows, func(out io.Writer) {
+		for _, issue := range issues {
+			fmt.Fprintf(out, "%s\t%s\t%s\n", issue.Name, issue.Type, issue.Status)
+		}
+	})
+}
+
+// Cells can't contain tabs or line breaks, bodies are written with escapes instead
+func tsvEscape(cell string) string {
+	return strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r").Replace(cell)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Json value with the order of object keys kept
+type orderedNode struct {
+	delim  json.Delim
+	keys   []string
+	values []*orderedNode
+	scalar string
+}
+
+func parseOrdered(decoder *json.Decoder) (*orderedNode, error) {
+	token, tokenErr := decoder.Token()
+	if tokenErr != nil {
+		return nil, tokenErr
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		node := &orderedNode{delim: value}
+		for decoder.More() {
+			if value == '{' {
+				key, keyErr := decoder.Token()
+				if keyErr != nil {
+					return nil, keyErr
+				}
+
+				node.keys = append(node.keys, key.(string))
+			}
+
+			child, childErr := parseOrdered(decoder)
+			if childErr != nil {
+				return nil, childErr
+			}
+
+			node.values = append(node.values, child)
+		}
+
+		_, endErr := decoder.Token()
+		return node, endErr
+	case string:
+		quoted, _ := json.Marshal(value)
+		return &orderedNode{scalar: string(quoted)}, nil
+	case json.Number:
+		return &orderedNode{scalar: value.String()}, nil
+	case bool:
+		return &orderedNode{scalar: strconv.FormatBool(value)}, nil
+	}
+
+	return &orderedNode{scalar: "null"}, nil
+}
+
+// Scalars and empty containers fit on the line of their key
+func (node *orderedNode) inline() (string, bool) {
+	switch {
+	case node.delim == 0:
+		return node.scalar, true
+	case len(node.values) > 0:
+		return "", false
+	case node.delim == '{':
+		return "{}", true
+	}
+
+	return "[]", true
+}
+
+func (node *orderedNode) writeYaml(yaml *strings.Builder, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	for index, value := range node.values {
+		if node.delim == '{' {
+			key := yamlKey(node.keys[index])
+			if scalar, ok := value.inline(); ok {
+				yaml.WriteString(prefix + key + ": " + scalar + "\n")
+				continue
+			}
+
+			yaml.WriteString(prefix + key + ":\n")
+			value.writeYaml(yaml, indent+1)
+			continue
+		}
+
+		if scalar, ok := value.inline(); ok {
+			yaml.WriteString(prefix + "- " + scalar + "\n")
+			continue
+		}
+
+		// Nested containers start on the line of their dash
+		var item strings.Builder
+		value.writeYaml(&item, indent+1)
+		yaml.WriteString(prefix + "- " + strings.TrimPrefix(item.String(), prefix+"  "))
+	}
+}
+
+// Words yaml parsers read as booleans or null when they are bare
+var yamlReservedWords = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true,
+	"true": true, "false": true, "null": true,
+}
+
+// Schema field names are written bare, anything else is quoted
+func yamlKey(key string) string {
+	if key != "" && !yamlReservedWords[key] && strings.Trim(key, "abcdefghijklmnopqrstuvwxyz_") == "" {
+		return key
+	}
+
+	quoted, _ := json.Marshal(key)
+	return string(quoted)
+}
+
+// Converts marshalled json to yaml, keeping the field order of the structs.
+// Strings are always double quoted, json string escapes are valid in yaml.
+func jsonToYaml(data []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	root, parseErr := parseOrdered(decoder)
+	if parseErr != nil {
+		return "", parseErr
+	}
+
+	if scalar, ok := root.inline(); ok {
+		return scalar + "\n", nil
+	}
+
+	var yaml strings.Builder
+	root.writeYaml(&yaml, 0)
+	return yaml.String(), nil
+}
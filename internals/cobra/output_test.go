@@ -0,0 +1,76 @@
+package cobra
+
+import (
+	"testing"
+)
+
+func TestJsonToYaml(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		yaml string
+	}{
+		{"scalar", `"Login"`, "\"Login\"\n"},
+		{"number", `2.5`, "2.5\n"},
+		{"null", `null`, "null\n"},
+		{"empty list", `[]`, "[]\n"},
+		{"empty object", `{}`, "{}\n"},
+		{
+			name: "field order is kept",
+			json: `{"name": "Login", "status": "todo", "estimate": 3, "blocked": false}`,
+			yaml: "name: \"Login\"\nstatus: \"todo\"\nestimate: 3\nblocked: false\n",
+		},
+		{
+			name: "escapes stay quoted",
+			json: `{"body": "line: one\n\"two\" # three"}`,
+			yaml: "body: \"line: one\\n\\\"two\\\" # three\"\n",
+		},
+		{
+			name: "keys outside the schema are quoted",
+			json: `{"Login": 1, "has space": 2, "": 3}`,
+			yaml: "\"Login\": 1\n\"has space\": 2\n\"\": 3\n",
+		},
+		{
+			name: "keys that look like yaml are quoted",
+			json: `{"a: b": 1, "# c": 2, "yes": 3, "no": 4, "null": 5, " d": 6, "- e": 7}`,
+			yaml: "\"a: b\": 1\n\"# c\": 2\n\"yes\": 3\n\"no\": 4\n\"null\": 5\n\" d\": 6\n\"- e\": 7\n",
+		},
+		{
+			name: "values that look like yaml are quoted",
+			json: `["a: b", "# c", "yes", "null", "3", " d", "- e", "[f]", "{g}", ""]`,
+			yaml: "- \"a: b\"\n- \"# c\"\n- \"yes\"\n- \"null\"\n- \"3\"\n- \" d\"\n- \"- e\"\n- \"[f]\"\n- \"{g}\"\n- \"\"\n",
+		},
+		{
+			name: "nested containers",
+			json: `{"issue": {"name": "Auth", "children": ["Login", "Logout"], "parents": []}}`,
+			yaml: "issue:\n  name: \"Auth\"\n  children:\n    - \"Login\"\n    - \"Logout\"\n  parents: []\n",
+		},
+		{
+			name: "objects in lists start on the dash",
+			json: `[{"issues": ["Auth", "Login"], "steps": []}, {"issues": [], "steps": ["Auth contains Login"]}]`,
+			yaml: "- issues:\n    - \"Auth\"\n    - \"Login\"\n  steps: []\n- issues: []\n  steps:\n    - \"Auth contains Login\"\n",
+		},
+		{
+			name: "lists in lists",
+			json: `[["Schema", "Api"], []]`,
+			yaml: "- - \"Schema\"\n  - \"Api\"\n- []\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			yaml, yamlErr := jsonToYaml([]byte(test.json))
+			if yamlErr != nil {
+				t.Fatal(yamlErr)
+			}
+
+			if yaml != test.yaml {
+				t.Errorf("yaml is\n%s\nwant\n%s", yaml, test.yaml)
+			}
+		})
+	}
+
+	if _, invalidErr := jsonToYaml([]byte(`{"name": `)); invalidErr == nil {
+		t.Error("expected truncated json to be rejected")
+	}
+}
@@ -0,0 +1,180 @@
+package cobra
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github/pm/pkg/config"
+	"github/pm/pkg/fileSystem"
+)
+
+// Name of the registered workspace to work on, overrides discovery
+var workspace string
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Register projects by name and switch between them",
+	Long:  "Register projects by name and switch between them. The current workspace is opened when pm runs outside of any project, --workspace opens one from anywhere",
+}
+
+var workspaceAddCmd = &cobra.Command{
+	Use:   "add <name> [directory]",
+	Short: "Register the project in directory, or the current project, under name",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var root string
+		if len(args) == 2 {
+			root = filepath.Join(args[1], fileSystem.PROJECT_DIRECTORY)
+			if !fileSystem.IsProjectRoot(root) {
+				return fileSystem.NotFoundError("No pm project found in " + args[1] + ", run pm init to create one")
+			}
+		} else {
+			found, rootErr := projectRoot()
+			if rootErr != nil {
+				return rootErr
+			}
+
+			root = found
+		}
+
+		root, absErr := filepath.Abs(root)
+		if absErr != nil {
+			return absErr
+		}
+
+		workspaces, loadErr := loadWorkspaces()
+		if loadErr != nil {
+			return loadErr
+		}
+
+		if _, ok := workspaces.Find(args[0]); ok {
+			return fileSystem.ConflictError("Workspace " + args[0] + " already exists, remove it first")
+		}
+
+		addErr := workspaces.Add(args[0], root)
+		if addErr != nil {
+			return fileSystem.InvalidError(addErr.Error())
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), "Added workspace "+args[0]+" at "+root)
+		return workspaces.Save()
+	},
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the workspaces, the current one is marked with *",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaces, loadErr := loadWorkspaces()
+		if loadErr != nil {
+			return loadErr
+		}
+
+		for _, entry := range workspaces.List {
+			marker := " "
+			if entry.Name == workspaces.Current {
+				marker = "*"
+			}
+
+			missing := ""
+			if !fileSystem.IsProjectRoot(entry.Root) {
+				missing = "  (missing)"
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s\t%s%s\n", marker, entry.Name, entry.Root, missing)
+		}
+
+		return nil
+	},
+}
+
+var workspaceUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a workspace the current one",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return useWorkspace(args[0])
+	},
+}
+
+var workspaceRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Forget a workspace, its project is left untouched",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaces, loadErr := loadWorkspaces()
+		if loadErr != nil {
+			return loadErr
+		}
+
+		if !workspaces.Remove(args[0]) {
+			return fileSystem.NotFoundError("Workspace " + args[0] + " does not exist")
+		}
+
+		return workspaces.Save()
+	},
+}
+
+func loadWorkspaces() (*config.Workspaces, error) {
+	path, pathErr := config.WorkspacesPath()
+	if pathErr != nil {
+		return nil, pathErr
+	}
+
+	workspaces, loadErr := config.LoadWorkspaces(path)
+	if loadErr != nil {
+		return nil, fileSystem.InvalidError(loadErr.Error())
+	}
+
+	return workspaces, nil
+}
+
+func useWorkspace(name string) error {
+	workspaces, loadErr := loadWorkspaces()
+	if loadErr != nil {
+		return loadErr
+	}
+
+	if _, ok := workspaces.Find(name); !ok {
+		return fileSystem.NotFoundError("Workspace " + name + " does not exist")
+	}
+
+	workspaces.Current = name
+	return workspaces.Save()
+}
+
+// The .pm directory of a registered workspace
+func workspaceRoot(name string) (string, error) {
+	workspaces, loadErr := loadWorkspaces()
+	if loadErr != nil {
+		return "", loadErr
+	}
+
+	entry, ok := workspaces.Find(name)
+	if !ok {
+		return "", fileSystem.NotFoundError("Workspace " + name + " does not exist, see pm workspace list")
+	}
+
+	return entry.Root, nil
+}
+
+func completeWorkspaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	workspaces, loadErr := loadWorkspaces()
+	if loadErr != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, entry := range workspaces.List {
+		names = append(names, entry.Name+"\t"+entry.Root)
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"errors"
 	"github/pm/pkg/fileSystem"
+	"log"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,7 +11,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-func NewApplication(fs *fileSystem.FileSystem) (tea.Model, error) {
+// keys maps pressed keys to the keys the frames handle, from the keys table of the config
+func NewApplication(fs *fileSystem.FileSystem, keys map[string]string) (tea.Model, error) {
 	stack := NewApplicationStack()
 	welcome := &WelcomeFrame{}
 	stack.Push(welcome)
@@ -40,10 +42,12 @@ func NewApplication(fs *fileSystem.FileSystem) (tea.Model, error) {
 
 	return Application{
 		History:       stack,
+		CommitError:   new(string),
 		Fs:            fs,
 		Renderer:      renderer,
 		ViewPort:      &vp,
 		GraphRenderer: &graphRenderer,
+		Keys:          keys,
 	}, nil
 }
 
@@ -53,6 +57,9 @@ type Application struct {
 	Renderer      *glamour.TermRenderer
 	ViewPort      *viewport.Model
 	GraphRenderer *fileSystem.FileGraphRenderer
+	Keys          map[string]string
+	Workspace     string  // Picked in the workspace switcher, pm reopens the TUI in it after quitting
+	CommitError   *string // Failed commit of the last change, shown under the frame
 }
 
 func (a Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -61,7 +68,20 @@ func (a Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, tea.Quit
 	}
 
-	return currentFrame.Update(msg, a)
+	model, cmd := currentFrame.Update(remapKey(msg, a.Keys), a)
+
+	// The error stays until the next commit is tried
+	attempted, commitErr := a.Fs.CommitChange()
+	if attempted {
+		*a.CommitError = ""
+	}
+
+	if commitErr != nil {
+		log.Println("Error committing changes", commitErr)
+		*a.CommitError = "Error committing changes: " + commitErr.Error()
+	}
+
+	return model, cmd
 }
 
 func (a Application) View() string {
@@ -73,6 +93,11 @@ func (a Application) View() string {
 
 	currentFrame.Refresh(a)
 
+	if *a.CommitError != "" {
+		errorStyle := lipgloss.NewStyle().Margin(0, 2).Foreground(lipgloss.Color("9"))
+		return currentFrame.View(a) + "\n" + errorStyle.Render(*a.CommitError)
+	}
+
 	return currentFrame.View(a)
 }
 func (a Application) Init() tea.Cmd {
@@ -0,0 +1,345 @@
+package application
+
+import (
+	"errors"
+	pmfile "github/pm/pkg/file"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Type filters cycled with [f], empty shows every type
+// Read on use, issue types are configured after the package is loaded
+func boardTypeFilters() []string {
+	return append([]string{""}, pmfile.FILE_TYPE_HIERARCHY...)
+}
+
+// Kanban board with a column per status, moving a card changes the status of its issue
+type BoardFrame struct {
+	column       int
+	cursors      []int
+	tops         []int
+	typeFilter   int
+	epic         string
+	subStack     *ApplicationStack
+	width        int
+	height       int
+	errorMessage string
+}
+
+func NewBoardFrame(app Application) (*BoardFrame, error) {
+	return &BoardFrame{
+		cursors:  make([]int, len(pmfile.FILE_STATUSES)),
+		tops:     make([]int, len(pmfile.FILE_STATUSES)),
+		subStack: NewApplicationStack(),
+		width:    120,
+		height:   30,
+	}, nil
+}
+
+func (bf BoardFrame) getFrame(app Application) (*BoardFrame, error) {
+	frame, error := app.History.Peek()
+	if error != nil {
+		return &BoardFrame{}, errors.New("Cannot get self")
+	}
+
+	boardFrame := frame.(*BoardFrame)
+	return boardFrame, nil
+}
+
+// Cards of every column after filtering, sorted by priority then name
+func (bf *BoardFrame) cards(app Application) ([][]string, error) {
+	files, filesErr := app.Fs.ListAllFilesWithTypes()
+	if filesErr != nil {
+		return nil, filesErr
+	}
+
+	var scope map[string]bool
+	if bf.epic != "" {
+		subtree, subtreeErr := app.Fs.HierarchySubtree(bf.epic)
+		if subtreeErr != nil {
+			// The epic was deleted since it was picked
+			log.Println("Dropping epic filter " + subtreeErr.Error())
+			bf.epic = ""
+		} else {
+			scope = subtree
+		}
+	}
+
+	columns := make([][]string, len(pmfile.FILE_STATUSES))
+	for fileType, fileNames := range files {
+		if boardTypeFilters()[bf.typeFilter] != "" && boardTypeFilters()[bf.typeFilter] != fileType {
+			continue
+		}
+
+		for _, fileName := range fileNames {
+			if scope != nil && (!scope[fileName] || fileName == bf.epic) {
+				continue
+			}
+
+			column := indexOf(pmfile.FILE_STATUSES, app.Fs.GetFileStatus(fileName))
+			if column == -1 {
+				continue
+			}
+
+			columns[column] = append(columns[column], fileName)
+		}
+	}
+
+	priorities := map[string]int{}
+	for index, priority := range pmfile.FILE_PRIORITIES {
+		priorities[priority] = index
+	}
+
+	for _, column := range columns {
+		sort.Slice(column, func(i, j int) bool {
+			left := priorities[app.Fs.GetFilePriority(column[i])]
+			right := priorities[app.Fs.GetFilePriority(column[j])]
+			if left != right {
+				return left < right
+			}
+
+			return column[i] < column[j]
+		})
+	}
+
+	return columns, nil
+}
+
+func (bf *BoardFrame) selected(app Application) (string, bool) {
+	columns, cardsErr := bf.cards(app)
+	if cardsErr != nil || len(columns[bf.column]) == 0 {
+		return "", false
+	}
+
+	return columns[bf.column][min(bf.cursors[bf.column], len(columns[bf.column])-1)], true
+}
+
+// Moves the selected card to the neighbouring column and follows it there
+func (bf *BoardFrame) moveCard(app Application, direction int) {
+	target := bf.column + direction
+	if target < 0 || target >= len(pmfile.FILE_STATUSES) {
+		return
+	}
+
+	issueId, ok := bf.selected(app)
+	if !ok {
+		return
+	}
+
+	statusErr := app.Fs.SetFileStatus(issueId, pmfile.FILE_STATUSES[target])
+	if statusErr != nil {
+		log.Println("Error moving card " + statusErr.Error())
+		bf.errorMessage = statusErr.Error()
+		return
+	}
+
+	bf.column = target
+	columns, cardsErr := bf.cards(app)
+	if cardsErr != nil {
+		return
+	}
+
+	bf.cursors[target] = max(indexOf(columns[target], issueId), 0)
+}
+
+func (bf BoardFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd) {
+	boardFrame, frameErr := bf.getFrame(app)
+	if frameErr != nil {
+		return app, tea.Quit
+	}
+
+	// Register epic filter selection
+	if boardFrame.subStack.Size() != 0 {
+		frame, frameErr := boardFrame.subStack.Peek()
+		if frameErr != nil {
+			return app, tea.Quit
+		}
+
+		selectedItem := frame.(*GlobalSelectionFrame).selectedItem
+		if selectedItem != "" {
+			index := strings.Index(selectedItem, "]")
+			boardFrame.epic = strings.TrimSpace(selectedItem[index+1:])
+			boardFrame.resetCursors()
+		}
+
+		boardFrame.subStack.ClearStack()
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		boardFrame.width = msg.Width
+		boardFrame.height = msg.Height
+	case tea.KeyMsg:
+		boardFrame.errorMessage = ""
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return app, tea.Quit
+		case "left":
+			app.History.Pop()
+		case "h":
+			boardFrame.column = max(boardFrame.column-1, 0)
+		case "l":
+			boardFrame.column = min(boardFrame.column+1, len(pmfile.FILE_STATUSES)-1)
+		case "up", "k":
+			boardFrame.cursors[boardFrame.column] = max(boardFrame.cursors[boardFrame.column]-1, 0)
+		case "down", "j":
+			columns, cardsErr := boardFrame.cards(app)
+			if cardsErr != nil {
+				return app, nil
+			}
+
+			cards := len(columns[boardFrame.column])
+			boardFrame.cursors[boardFrame.column] = max(min(boardFrame.cursors[boardFrame.column]+1, cards-1), 0)
+		case "H":
+			boardFrame.moveCard(app, -1)
+		case "L":
+			boardFrame.moveCard(app, 1)
+		case "f":
+			boardFrame.typeFilter = (boardFrame.typeFilter + 1) % len(boardTypeFilters())
+			boardFrame.resetCursors()
+		case "E":
+			globalSearchFrame, frameErr := NewGlobalSelectionFrameOfTypes(app, "", nil, []string{pmfile.FILE_TYPE_EPIC})
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(globalSearchFrame)
+			boardFrame.subStack.Push(globalSearchFrame)
+		case "c":
+			boardFrame.typeFilter = 0
+			boardFrame.epic = ""
+			boardFrame.resetCursors()
+		case "v":
+			issueId, ok := boardFrame.selected(app)
+			if !ok {
+				return app, nil
+			}
+
+			content, contentErr := app.Fs.RetrieveFileContents(issueId)
+			if contentErr != nil {
+				return app, tea.Quit
+			}
+
+			mdFrame, frameErr := NewViewMarkdownFrame(issueId, content, app)
+			if frameErr != nil {
+				return app, tea.Quit
+			}
+
+			app.History.Push(mdFrame)
+		case "o":
+			issueId, ok := boardFrame.selected(app)
+			if ok {
+				app.Fs.EditFile(issueId)
+			}
+		}
+	}
+
+	return app, nil
+}
+
+func (bf *BoardFrame) resetCursors() {
+	for index := range bf.cursors {
+		bf.cursors[index] = 0
+		bf.tops[index] = 0
+	}
+}
+
+func (bf BoardFrame) View(app Application) string {
+	boardFrame, frameErr := bf.getFrame(app)
+	if frameErr != nil {
+		return ""
+	}
+
+	columns, cardsErr := boardFrame.cards(app)
+	if cardsErr != nil {
+		return ""
+	}
+
+	columnWidth := max((boardFrame.width-4)/len(columns)-2, 12)
+	visibleCards := max(boardFrame.height-12, 1)
+	columnStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Width(columnWidth).PaddingLeft(1)
+	focusedStyle := columnStyle.BorderForeground(lipgloss.Color("170"))
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+
+	var renderedColumns []string
+	for index, cards := range columns {
+		// Cards can move out of a column from under the cursor
+		cursor := min(boardFrame.cursors[index], max(len(cards)-1, 0))
+		boardFrame.cursors[index] = cursor
+
+		top := boardFrame.tops[index]
+		if cursor < top {
+			top = cursor
+		} else if cursor >= top+visibleCards {
+			top = cursor - visibleCards + 1
+		}
+		top = min(top, max(len(cards)-visibleCards, 0))
+		boardFrame.tops[index] = top
+
+		lines := []string{pmfile.FILE_STATUSES[index] + " (" + strconv.Itoa(len(cards)) + ")", ""}
+		if top > 0 {
+			lines = append(lines, "  ↑ "+strconv.Itoa(top)+" more")
+		}
+
+		for card := top; card < min(top+visibleCards, len(cards)); card++ {
+			name := truncate(cards[card], columnWidth-3)
+			if index == boardFrame.column && card == cursor {
+				lines = append(lines, cursorStyle.Render("> "+name))
+				continue
+			}
+
+			lines = append(lines, "  "+name)
+		}
+
+		if hidden := len(cards) - top - visibleCards; hidden > 0 {
+			lines = append(lines, "  ↓ "+strconv.Itoa(hidden)+" more")
+		}
+
+		style := columnStyle
+		if index == boardFrame.column {
+			style = focusedStyle
+		}
+
+		renderedColumns = append(renderedColumns, style.Render(strings.Join(lines, "\n")))
+	}
+
+	filters := "All types"
+	if boardTypeFilters()[boardFrame.typeFilter] != "" {
+		filters = "Type " + boardTypeFilters()[boardFrame.typeFilter]
+	}
+
+	if boardFrame.epic != "" {
+		filters += " ● Epic " + boardFrame.epic
+	}
+
+	helptext := filters + "\n[h/l] Column ● [↑/↓] Card ● [H/L] Move card ● [f] Filter type ● [E] Filter epic ● [c] Clear filters\n[v] View File ● [o] Open ● [q] Quit ● [←] Back"
+	if boardFrame.errorMessage != "" {
+		helptext = boardFrame.errorMessage + "\n" + helptext
+	}
+
+	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+	board := lipgloss.JoinHorizontal(lipgloss.Top, renderedColumns...)
+	return titleStyle.Render("  Board") + "\n" + board + marginStyle.Render(helptext)
+}
+
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width || width < 1 {
+		return value
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
+func (bf BoardFrame) Init(app Application) tea.Cmd {
+	return nil
+}
+
+func (bf BoardFrame) Refresh(app Application) error {
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 type BrowseFrame struct {
 	epics    list.Model
 	fileType string
+	bulk     bulkSelection
 }
 
 func NewBrowseFrame(app Application, fileType string) ApplicationFrame {
@@ -29,7 +30,8 @@ func NewBrowseFrame(app Application, fileType string) ApplicationFrame {
 		epicItems = append(epicItems, item(epic))
 	}
 
-	delegate := itemDelegate{}
+	bulk := newBulkSelection()
+	delegate := markableItemDelegate{marked: bulk.marked}
 
 	const defaultWidth = 50
 	l := list.New(epicItems, delegate, defaultWidth, 14)
@@ -47,6 +49,7 @@ func NewBrowseFrame(app Application, fileType string) ApplicationFrame {
 	bf := BrowseFrame{
 		epics:    l,
 		fileType: fileType,
+		bulk:     bulk,
 	}
 
 	return &bf
@@ -93,6 +96,9 @@ func (bf BrowseFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd)
 		return app, tea.Quit
 	}
 
+	// Register bulk action selections
+	browseFrame.bulk.resolve(app)
+
 	if len(browseFrame.epics.Items()) == 0 {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
@@ -106,6 +112,10 @@ func (bf BrowseFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd)
 	} else {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			if browseFrame.bulk.handleKey(msg.String(), app, &browseFrame.epics) {
+				return app, nil
+			}
+
 			switch msg.String() {
 			case "q", "ctrl+c", "esc":
 				return app, tea.Quit
@@ -216,6 +226,8 @@ func (bf BrowseFrame) View(app Application) string {
 		helptext = helptext + taskText
 	}
 
+	helptext = helptext + "\n" + browseFrame.bulk.helpText()
+
 	return bf.epics.View() + marginStyle.Render(helptext)
 }
 
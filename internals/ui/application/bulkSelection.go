@@ -0,0 +1,268 @@
+package application
+
+import (
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+const (
+	bulkActionStatus = "status"
+	bulkActionMove   = "move"
+	bulkActionDepend = "depend"
+	bulkActionDelete = "delete"
+)
+
+// Marks issues in a list frame so that an action can be applied to all of them at once.
+// Shared by the list frames, actions fall back to the selected item when nothing is marked.
+type bulkSelection struct {
+	marked         map[string]bool
+	subStack       *ApplicationStack
+	pendingAction  string
+	pendingTargets []string
+	errorMessage   string
+}
+
+func newBulkSelection() bulkSelection {
+	return bulkSelection{
+		marked:   map[string]bool{},
+		subStack: NewApplicationStack(),
+	}
+}
+
+// Renders list items like itemDelegate and flags the marked ones
+type markableItemDelegate struct {
+	itemDelegate
+	marked map[string]bool
+}
+
+func (d markableItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(item)
+	if !ok {
+		return
+	}
+
+	if !d.marked[string(i)] {
+		d.itemDelegate.Render(w, m, index, listItem)
+		return
+	}
+
+	d.itemDelegate.Render(w, m, index, item("[x] "+string(i)))
+}
+
+func (bs *bulkSelection) toggle(fileName string) {
+	if bs.marked[fileName] {
+		delete(bs.marked, fileName)
+		return
+	}
+
+	bs.marked[fileName] = true
+}
+
+// Returns the marked issues that are still listed, or the selected issue when nothing is marked
+func (bs *bulkSelection) targets(l list.Model) []string {
+	var targets []string
+	for _, listItem := range l.Items() {
+		fileName := string(listItem.(item))
+		if bs.marked[fileName] {
+			targets = append(targets, fileName)
+		}
+	}
+
+	if len(targets) > 0 {
+		return targets
+	}
+
+	selectedItem, ok := l.SelectedItem().(item)
+	if !ok {
+		return nil
+	}
+
+	return []string{string(selectedItem)}
+}
+
+// Clears in place as the list delegate holds on to the map
+func (bs *bulkSelection) clear() {
+	for fileName := range bs.marked {
+		delete(bs.marked, fileName)
+	}
+}
+
+// Starts the bulk action bound to key. Returns false if key is not a bulk action.
+func (bs *bulkSelection) handleKey(key string, app Application, l *list.Model) bool {
+	switch key {
+	case " ":
+		selectedItem, ok := l.SelectedItem().(item)
+		if ok {
+			bs.toggle(string(selectedItem))
+		}
+
+		l.CursorDown()
+		return true
+	case "D":
+		targets := bs.targets(*l)
+		if len(targets) == 0 {
+			return true
+		}
+
+		deleteFrame, frameErr := NewDeleteIssuesFrame(app, targets, false)
+		if frameErr != nil {
+			bs.errorMessage = frameErr.Error()
+			return true
+		}
+
+		bs.start(app, deleteFrame, bulkActionDelete, targets)
+		return true
+	case "S":
+		statusFrame := NewOptionSelectionFrame("Change status to", pmfile.FILE_STATUSES)
+		bs.start(app, statusFrame, bulkActionStatus, bs.targets(*l))
+		return true
+	case "M":
+		targets := bs.targets(*l)
+		parentTypes, typesErr := commonParentTypes(app, targets)
+		if typesErr != nil {
+			bs.errorMessage = typesErr.Error()
+			return true
+		}
+
+		if len(parentTypes) == 0 {
+			bs.errorMessage = "The selected issues cannot share a parent"
+			return true
+		}
+
+		globalSearchFrame, frameErr := NewGlobalSelectionFrameOfTypes(app, "", targets, parentTypes)
+		if frameErr != nil {
+			bs.errorMessage = frameErr.Error()
+			return true
+		}
+
+		bs.start(app, globalSearchFrame, bulkActionMove, targets)
+		return true
+	case "B":
+		targets := bs.targets(*l)
+		globalSearchFrame, frameErr := NewGlobalSelectionFrame(app, "", targets)
+		if frameErr != nil {
+			bs.errorMessage = frameErr.Error()
+			return true
+		}
+
+		bs.start(app, globalSearchFrame, bulkActionDepend, targets)
+		return true
+	}
+
+	return false
+}
+
+func (bs *bulkSelection) start(app Application, frame ApplicationFrame, action string, targets []string) {
+	if len(targets) == 0 {
+		return
+	}
+
+	bs.pendingAction = action
+	bs.pendingTargets = targets
+	bs.errorMessage = ""
+	app.History.Push(frame)
+	bs.subStack.Push(frame)
+}
+
+// Applies a pending bulk action once its selection frame has been closed
+func (bs *bulkSelection) resolve(app Application) {
+	if bs.subStack.Size() == 0 {
+		return
+	}
+
+	frame, frameErr := bs.subStack.Peek()
+	if frameErr != nil {
+		return
+	}
+
+	var selection string
+	switch selectionFrame := frame.(type) {
+	case *GlobalSelectionFrame:
+		if selectionFrame.selectedItem != "" {
+			index := strings.Index(selectionFrame.selectedItem, "]")
+			selection = strings.TrimSpace(selectionFrame.selectedItem[index+1:])
+		}
+	case *OptionSelectionFrame:
+		selection = selectionFrame.selectedItem
+	case *DeleteIssueFrame:
+		// The dialog deletes the issues itself and shows its own errors
+		if selectionFrame.deleted {
+			selection = bulkActionDelete
+		}
+	}
+
+	bs.subStack.ClearStack()
+	action := bs.pendingAction
+	targets := bs.pendingTargets
+	bs.pendingAction = ""
+	bs.pendingTargets = nil
+
+	if selection == "" {
+		return
+	}
+
+	var actionErr error
+	switch action {
+	case bulkActionStatus:
+		actionErr = app.Fs.SetIssuesStatus(targets, selection)
+	case bulkActionMove:
+		actionErr = app.Fs.MoveIssues(targets, selection)
+	case bulkActionDepend:
+		actionErr = app.Fs.LinkDependencies(selection, targets)
+	}
+
+	if actionErr != nil {
+		log.Println("Error applying bulk action " + actionErr.Error())
+		bs.errorMessage = actionErr.Error()
+		return
+	}
+
+	bs.errorMessage = ""
+	bs.clear()
+}
+
+func (bs *bulkSelection) helpText() string {
+	helptext := "[space] Mark ● [D] Delete ● [S] Set status ● [M] Move to parent ● [B] Add upstream blocker"
+	if len(bs.marked) > 0 {
+		helptext = strconv.Itoa(len(bs.marked)) + " marked ● " + helptext
+	}
+
+	if bs.errorMessage != "" {
+		helptext = bs.errorMessage + "\n" + helptext
+	}
+
+	return helptext
+}
+
+// File types that can be the parent of every one of the issues
+func commonParentTypes(app Application, fileNames []string) ([]string, error) {
+	counts := map[string]int{}
+	for _, fileName := range fileNames {
+		fileType, typeErr := app.Fs.GetFileType(fileName)
+		if typeErr != nil {
+			return nil, typeErr
+		}
+
+		for _, parentType := range fileSystem.ValidParentTypes(fileType) {
+			counts[parentType]++
+		}
+	}
+
+	var parentTypes []string
+	for parentType, count := range counts {
+		if count == len(fileNames) {
+			parentTypes = append(parentTypes, parentType)
+		}
+	}
+
+	sort.Strings(parentTypes)
+	return parentTypes, nil
+}
@@ -15,6 +15,7 @@ type ChildIssueFrame struct {
 	relationship string
 	fileName     string
 	direction    bool
+	bulk         bulkSelection
 }
 
 func NewChildIssueFrame(app Application, fileName string, childRelationship string, direction bool) (ApplicationFrame, error) {
@@ -40,8 +41,10 @@ func NewChildIssueFrame(app Application, fileName string, childRelationship stri
 		return ChildIssueFrame{}, typeErr
 	}
 
+	bulk := newBulkSelection()
+
 	const defaultWidth = 200
-	l := list.New(issueItems, itemDelegate{}, defaultWidth, 14)
+	l := list.New(issueItems, markableItemDelegate{marked: bulk.marked}, defaultWidth, 14)
 	l.Title = "[" + fileName + "]\n" + pageTitle 
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
@@ -58,6 +61,7 @@ func NewChildIssueFrame(app Application, fileName string, childRelationship stri
 		relationship: childRelationship,
 		fileName:     fileName,
 		direction:    direction,
+		bulk:         bulk,
 	}
 
 	return &bf, nil
@@ -124,6 +128,9 @@ func (cif ChildIssueFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.
 		return app, tea.Quit
 	}
 
+	// Register bulk action selections
+	browseFrame.bulk.resolve(app)
+
 	if len(browseFrame.children.Items()) == 0 {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
@@ -137,6 +144,10 @@ func (cif ChildIssueFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.
 	} else {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			if browseFrame.bulk.handleKey(msg.String(), app, &browseFrame.children) {
+				return app, nil
+			}
+
 			switch msg.String() {
 			case "q", "ctrl+c", "esc":
 				return app, tea.Quit
@@ -257,7 +268,7 @@ func (cif ChildIssueFrame) View(app Application) string {
 		return cif.children.View() + marginStyle.Render("[q] Quit ● [←] Back")
 	}
 
-	helptext := "[v] View File ● [c] list Children ● [d] List Downstream dependencies ● [u] List Upstream depedencies [r] Unlink issue\n[q] Quit ● [←] Back \n[e] All epics [s] All stories [t] All tasks"
+	helptext := "[v] View File ● [c] list Children ● [d] List Downstream dependencies ● [u] List Upstream depedencies [r] Unlink issue\n[q] Quit ● [←] Back \n[e] All epics [s] All stories [t] All tasks\n" + browseFrame.bulk.helpText()
 	return cif.children.View() + marginStyle.Render(helptext)
 }
 
@@ -0,0 +1,172 @@
+package application
+
+import (
+	"errors"
+	"github/pm/pkg/fileSystem"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	deleteOptionDelete   = "Delete issue"
+	deleteOptionCascade  = "Delete issue and all child issues"
+	deleteOptionReparent = "Delete issue and move child issues to its parent"
+	deleteOptionCancel   = "Cancel"
+)
+
+type DeleteIssueFrame struct {
+	options         list.Model
+	fileNames       []string
+	children        int
+	closeIssueFrame bool
+	errorMessage    string
+	deleted         bool // Set once the issues are gone, for the bulk selection that opened the dialog
+}
+
+// Confirms the deletion of the issue shown by the frame below the dialog
+func NewDeleteIssueFrame(app Application, fileName string) (*DeleteIssueFrame, error) {
+	return NewDeleteIssuesFrame(app, []string{fileName}, true)
+}
+
+// closeIssueFrame also pops the frame below the dialog once the issues are deleted
+func NewDeleteIssuesFrame(app Application, fileNames []string, closeIssueFrame bool) (*DeleteIssueFrame, error) {
+	children := 0
+	for _, fileName := range fileNames {
+		fileChildren, childrenErr := app.Fs.ListRelatedHierarchy(fileName)
+		if childrenErr != nil {
+			return &DeleteIssueFrame{}, childrenErr
+		}
+
+		children += len(fileChildren)
+	}
+
+	var items []list.Item
+	if children == 0 {
+		items = []list.Item{
+			item(deleteOptionDelete),
+			item(deleteOptionCancel),
+		}
+	} else {
+		items = []list.Item{
+			item(deleteOptionReparent),
+			item(deleteOptionCascade),
+			item(deleteOptionCancel),
+		}
+	}
+
+	const defaultWidth = 100
+	l := list.New(items, itemDelegate{}, defaultWidth, len(items)+5)
+	if len(fileNames) == 1 {
+		l.Title = "[" + fileNames[0] + "]\nDelete issue"
+	} else {
+		l.Title = "Delete " + strconv.Itoa(len(fileNames)) + " issues"
+	}
+
+	if children > 0 {
+		l.Title = l.Title + " with " + strconv.Itoa(children) + " child issues"
+	}
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.SetShowHelp(false)
+
+	return &DeleteIssueFrame{
+		options:         l,
+		fileNames:       fileNames,
+		children:        children,
+		closeIssueFrame: closeIssueFrame,
+	}, nil
+}
+
+func (dif DeleteIssueFrame) getFrame(app Application) (*DeleteIssueFrame, error) {
+	frame, error := app.History.Peek()
+	if error != nil {
+		return &DeleteIssueFrame{}, errors.New("Cannot get self")
+	}
+
+	deleteIssueFrame := frame.(*DeleteIssueFrame)
+	return deleteIssueFrame, nil
+}
+
+func (dif DeleteIssueFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd) {
+	deleteIssueFrame, frameErr := dif.getFrame(app)
+	if frameErr != nil {
+		return app, tea.Quit
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return app, tea.Quit
+		case "left":
+			app.History.Pop()
+			return app, nil
+		case "enter":
+			selectedItem, ok := deleteIssueFrame.options.SelectedItem().(item)
+			if !ok {
+				return app, nil
+			}
+
+			var mode string
+			switch string(selectedItem) {
+			case deleteOptionDelete:
+				mode = fileSystem.DELETE_MODE_REFUSE
+			case deleteOptionCascade:
+				mode = fileSystem.DELETE_MODE_CASCADE
+			case deleteOptionReparent:
+				mode = fileSystem.DELETE_MODE_REPARENT
+			default:
+				app.History.Pop()
+				return app, nil
+			}
+
+			deleteErr := app.Fs.DeleteIssues(deleteIssueFrame.fileNames, mode)
+			if deleteErr != nil {
+				deleteIssueFrame.errorMessage = deleteErr.Error()
+				return app, nil
+			}
+
+			deleteIssueFrame.deleted = true
+			app.History.Pop()
+			if deleteIssueFrame.closeIssueFrame {
+				app.History.Pop()
+			}
+
+			return app, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	deleteIssueFrame.options, cmd = deleteIssueFrame.options.Update(msg)
+	return app, cmd
+}
+
+func (dif DeleteIssueFrame) View(app Application) string {
+	deleteIssueFrame, frameErr := dif.getFrame(app)
+	if frameErr != nil {
+		return ""
+	}
+
+	helptext := "\n[q] Quit ● [←] Back ● [enter] Enter"
+	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+
+	if deleteIssueFrame.errorMessage != "" {
+		errorText := "\n " + deleteIssueFrame.errorMessage
+		return deleteIssueFrame.options.View() + marginStyle.Render(errorText) + marginStyle.Render(helptext)
+	}
+
+	return deleteIssueFrame.options.View() + marginStyle.Render(helptext)
+}
+
+func (dif DeleteIssueFrame) Init(app Application) tea.Cmd {
+	return nil
+}
+
+func (dif DeleteIssueFrame) Refresh(app Application) error {
+	return nil
+}
@@ -2,28 +2,86 @@ package application
 
 import (
 	"errors"
+	"github/pm/pkg/fileSystem"
+	"log"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+const panStep = 4
+
+// Edge labels shown by the graph, cycled with [t]
+var graphLabelModes = [][]string{
+	fileSystem.FILE_RELATIONSHIPS,
+	{fileSystem.FILE_RELATIONSHIPS_HIERARCHY},
+	{fileSystem.FILE_RELATIONSHIP_DEPENDENCY},
+}
+
+var graphLabelModeNames = []string{"All edges", "Contains only", "Blocks only"}
+
 type DependencyGraph struct {
-	fileName string
+	fileName  string
+	selected  string
+	collapsed map[string]bool
+	labelMode int
+	offsetX   int
+	top       int
+	width     int
+	height    int
 }
 
 func NewDependencyGraph(fileName string) (*DependencyGraph, error) {
 	return &DependencyGraph{
-		fileName: fileName,
+		fileName:  fileName,
+		selected:  fileName,
+		collapsed: map[string]bool{},
+		width:     78,
+		height:    20,
 	}, nil
 }
 
 func (dg DependencyGraph) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd) {
+	depGraphFrame, frameErr := dg.getFrame(app)
+	if frameErr != nil {
+		return app, tea.Quit
+	}
+
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		depGraphFrame.width = msg.Width
+		depGraphFrame.height = msg.Height
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "ctrl+c", "esc":
 			return app, tea.Quit
 		case "left":
 			app.History.Pop()
+		case "up", "k":
+			depGraphFrame.moveCursor(app, -1)
+		case "down", "j":
+			depGraphFrame.moveCursor(app, 1)
+		case "h", "shift+left":
+			depGraphFrame.offsetX = max(depGraphFrame.offsetX-panStep, 0)
+		case "l", "shift+right":
+			depGraphFrame.offsetX += panStep
+		case " ":
+			depGraphFrame.collapsed[depGraphFrame.selected] = !depGraphFrame.collapsed[depGraphFrame.selected]
+		case "t":
+			depGraphFrame.labelMode = (depGraphFrame.labelMode + 1) % len(graphLabelModes)
+		case "enter", "v":
+			content, contentErr := app.Fs.RetrieveFileContents(depGraphFrame.selected)
+			if contentErr != nil {
+				return app, nil
+			}
+
+			mdFrame, frameErr := NewViewMarkdownFrame(depGraphFrame.selected, content, app)
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(mdFrame)
 		}
 	}
 
@@ -40,23 +98,112 @@ func (dg DependencyGraph) getFrame(app Application) (*DependencyGraph, error) {
 	return depGraphframe, nil
 }
 
-func (dg DependencyGraph) View(app Application) string {
-	helptext := "\n[q] Quit ● [←] Back "
-	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+func (dg *DependencyGraph) rows(app Application) ([]fileSystem.GraphRow, error) {
+	// The graph is still drawn without highlights when the critical path can't be found
+	criticalPath, _, pathErr := app.Fs.CriticalPath("")
+	if pathErr != nil {
+		log.Println("Error finding critical path " + pathErr.Error())
+	}
+
+	renderer := *app.GraphRenderer
+	renderer.Labels = graphLabelModes[dg.labelMode]
+	renderer.Collapsed = dg.collapsed
+	renderer.Highlighted = map[string]bool{}
+	for _, fileName := range criticalPath {
+		renderer.Highlighted[fileName] = true
+	}
+
+	return renderer.Rows(dg.fileName)
+}
 
+// Moves the selection to the next issue above or below, skipping rows that only connect lanes
+func (dg *DependencyGraph) moveCursor(app Application, direction int) {
+	rows, rowsErr := dg.rows(app)
+	if rowsErr != nil {
+		return
+	}
+
+	var issues []string
+	for _, row := range rows {
+		if row.FileName != "" {
+			issues = append(issues, row.FileName)
+		}
+	}
+
+	index := 0
+	for i, issue := range issues {
+		if issue == dg.selected {
+			index = i
+		}
+	}
+
+	index = min(max(index+direction, 0), len(issues)-1)
+	if index >= 0 {
+		dg.selected = issues[index]
+	}
+}
+
+func (dg DependencyGraph) View(app Application) string {
 	depGraphFrame, frameErr := dg.getFrame(app)
 	if frameErr != nil {
 		return ""
 	}
 
-	// vertex := app.Fs.GetFileChildMeta(depGraphFrame.fileName)
+	helptext := "\n[│] Contains ● [┆] Blocks ● [◆] Critical path ● [+] Collapsed\n" +
+		"[↑/↓] Move ● [h/l] Pan ● [space] Collapse/expand ● [t] " + graphLabelModeNames[depGraphFrame.labelMode] + " ● [v] View File\n" +
+		"[q] Quit ● [←] Back "
+	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+
+	rows, rowsErr := depGraphFrame.rows(app)
+	if rowsErr != nil {
+		return ""
+	}
+
+	selectedRow := 0
+	for index, row := range rows {
+		if row.FileName == depGraphFrame.selected {
+			selectedRow = index
+		}
+	}
+
+	// Keep the selected issue in view
+	visibleRows := max(depGraphFrame.height-8, 1)
+	if selectedRow < depGraphFrame.top {
+		depGraphFrame.top = selectedRow
+	} else if selectedRow >= depGraphFrame.top+visibleRows {
+		depGraphFrame.top = selectedRow - visibleRows + 1
+	}
+	depGraphFrame.top = min(depGraphFrame.top, max(len(rows)-visibleRows, 0))
+
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	var lines []string
+	for index := depGraphFrame.top; index < min(depGraphFrame.top+visibleRows, len(rows)); index++ {
+		row := rows[index]
+		line := row.Lanes + " " + row.FileName
+		if row.Collapsed {
+			line += " [+]"
+		}
+
+		line = panLine(strings.TrimRight(line, " "), depGraphFrame.offsetX, max(depGraphFrame.width-4, 1))
+		if row.FileName != "" && row.FileName == depGraphFrame.selected {
+			lines = append(lines, cursorStyle.Render("> "+line))
+			continue
+		}
+
+		lines = append(lines, "  "+line)
+	}
+
+	return "\n" + strings.Join(lines, "\n") + marginStyle.Render(helptext)
+}
 
-	graph, renderErr := app.GraphRenderer.Build(depGraphFrame.fileName)
-	if renderErr != nil {
+// Cuts the part of line between offset and offset+width
+func panLine(line string, offset int, width int) string {
+	runes := []rune(line)
+	if offset >= len(runes) {
 		return ""
 	}
 
-	return graph + marginStyle.Render(helptext)
+	return string(runes[offset:min(offset+width, len(runes))])
 }
 
 func (dg DependencyGraph) Init(app Application) tea.Cmd {
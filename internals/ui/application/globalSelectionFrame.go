@@ -25,6 +25,11 @@ func indexOf(haystack []string, needle string) int {
 }
 
 func NewGlobalSelectionFrame(app Application, currentFile string, excludeFiles []string) (*GlobalSelectionFrame, error) {
+	return NewGlobalSelectionFrameOfTypes(app, currentFile, excludeFiles, nil)
+}
+
+// Only lists issues of the given file types, all file types are listed when fileTypes is nil
+func NewGlobalSelectionFrameOfTypes(app Application, currentFile string, excludeFiles []string, fileTypes []string) (*GlobalSelectionFrame, error) {
 	// Fetch all issues
 	filesWithTypes, fsErr := app.Fs.ListAllFilesWithTypes()
 	if fsErr != nil {
@@ -35,6 +40,10 @@ func NewGlobalSelectionFrame(app Application, currentFile string, excludeFiles [
 	fileItemList := make([]list.Item, 0)
 	fileList := make([]string, 0)
 	for fileType, files := range filesWithTypes {
+		if fileTypes != nil && indexOf(fileTypes, fileType) == -1 {
+			continue
+		}
+
 		for _, fileName := range files {
 			if fileName == currentFile {
 				continue
@@ -0,0 +1,296 @@
+package application
+
+import (
+	"errors"
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Outline of the whole HIERARCHY structure, issues without a parent at the top
+type HierarchyTreeFrame struct {
+	expanded     map[string]bool
+	cursor       int
+	top          int
+	height       int
+	errorMessage string
+}
+
+type treeRow struct {
+	fileName string
+	depth    int
+}
+
+// Descendant counts of an issue, done counts the descendants marked done
+type treeRollUp struct {
+	done  int
+	total int
+}
+
+func NewHierarchyTreeFrame(app Application) (*HierarchyTreeFrame, error) {
+	return &HierarchyTreeFrame{
+		expanded: map[string]bool{},
+		height:   20,
+	}, nil
+}
+
+func (htf HierarchyTreeFrame) getFrame(app Application) (*HierarchyTreeFrame, error) {
+	frame, error := app.History.Peek()
+	if error != nil {
+		return &HierarchyTreeFrame{}, errors.New("Cannot get self")
+	}
+
+	treeFrame := frame.(*HierarchyTreeFrame)
+	return treeFrame, nil
+}
+
+// Issues without a hierarchy parent, ordered epics first
+func treeRoots(app Application) ([]string, error) {
+	var roots []string
+	for _, fileType := range pmfile.FILE_TYPE_HIERARCHY {
+		fileNames, listErr := app.Fs.ListFileNamesByType(fileType)
+		if listErr != nil {
+			return nil, listErr
+		}
+
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			parents, parentsErr := app.Fs.ListRelatedParents(fileName, fileSystem.FILE_RELATIONSHIPS_HIERARCHY)
+			if parentsErr != nil {
+				return nil, parentsErr
+			}
+
+			if len(parents) == 0 {
+				roots = append(roots, fileName)
+			}
+		}
+	}
+
+	return roots, nil
+}
+
+func treeChildren(app Application, fileName string) []string {
+	children, childrenErr := app.Fs.ListRelatedHierarchy(fileName)
+	if childrenErr != nil {
+		return nil
+	}
+
+	sort.Strings(children)
+	return children
+}
+
+// Flattens the expanded part of the tree into the rows shown on screen
+func (htf *HierarchyTreeFrame) rows(app Application) ([]treeRow, error) {
+	roots, rootsErr := treeRoots(app)
+	if rootsErr != nil {
+		return nil, rootsErr
+	}
+
+	var rows []treeRow
+	var walk func(fileName string, depth int)
+	walk = func(fileName string, depth int) {
+		rows = append(rows, treeRow{fileName: fileName, depth: depth})
+		if !htf.expanded[fileName] {
+			return
+		}
+
+		for _, child := range treeChildren(app, fileName) {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		walk(root, 0)
+	}
+
+	return rows, nil
+}
+
+// Counts every issue below fileName once, even when it is reached through several parents
+func rollUp(app Application, fileName string, subtrees map[string]map[string]bool) treeRollUp {
+	var total treeRollUp
+	for descendant := range subtree(app, fileName, subtrees) {
+		total.total++
+		if app.Fs.GetFileStatus(descendant) == pmfile.FILE_STATUS_DONE {
+			total.done++
+		}
+	}
+
+	return total
+}
+
+// Issues below fileName in the hierarchy, cached per issue
+func subtree(app Application, fileName string, subtrees map[string]map[string]bool) map[string]bool {
+	if cached, ok := subtrees[fileName]; ok {
+		return cached
+	}
+
+	below := map[string]bool{}
+	for _, child := range treeChildren(app, fileName) {
+		below[child] = true
+		for descendant := range subtree(app, child, subtrees) {
+			below[descendant] = true
+		}
+	}
+
+	subtrees[fileName] = below
+	return below
+}
+
+func (htf *HierarchyTreeFrame) selected(app Application) (string, bool) {
+	rows, rowsErr := htf.rows(app)
+	if rowsErr != nil || len(rows) == 0 {
+		return "", false
+	}
+
+	return rows[min(htf.cursor, len(rows)-1)].fileName, true
+}
+
+func (htf HierarchyTreeFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd) {
+	treeFrame, frameErr := htf.getFrame(app)
+	if frameErr != nil {
+		return app, tea.Quit
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		treeFrame.height = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return app, tea.Quit
+		case "left":
+			app.History.Pop()
+			return app, nil
+		case "up", "k":
+			treeFrame.cursor = max(treeFrame.cursor-1, 0)
+			return app, nil
+		case "down", "j":
+			rows, rowsErr := treeFrame.rows(app)
+			if rowsErr != nil {
+				return app, nil
+			}
+
+			treeFrame.cursor = max(min(treeFrame.cursor+1, len(rows)-1), 0)
+			return app, nil
+		}
+
+		issueId, ok := treeFrame.selected(app)
+		if !ok {
+			return app, nil
+		}
+
+		treeFrame.errorMessage = ""
+		switch msg.String() {
+		case " ", "enter":
+			treeFrame.expanded[issueId] = !treeFrame.expanded[issueId]
+		case "v":
+			content, contentErr := app.Fs.RetrieveFileContents(issueId)
+			if contentErr != nil {
+				return app, tea.Quit
+			}
+
+			mdFrame, frameErr := NewViewMarkdownFrame(issueId, content, app)
+			if frameErr != nil {
+				return app, tea.Quit
+			}
+
+			app.History.Push(mdFrame)
+		case "o":
+			app.Fs.EditFile(issueId)
+		case "i":
+			createFormFrame, frameErr := NewCreateFormFrame(app, issueId)
+			if frameErr != nil {
+				treeFrame.errorMessage = frameErr.Error()
+				return app, nil
+			}
+
+			treeFrame.expanded[issueId] = true
+			app.History.Push(createFormFrame)
+		case "r":
+			deleteFrame, frameErr := NewDeleteIssuesFrame(app, []string{issueId}, false)
+			if frameErr != nil {
+				treeFrame.errorMessage = frameErr.Error()
+				return app, nil
+			}
+
+			app.History.Push(deleteFrame)
+		}
+	}
+
+	return app, nil
+}
+
+func (htf HierarchyTreeFrame) View(app Application) string {
+	treeFrame, frameErr := htf.getFrame(app)
+	if frameErr != nil {
+		return ""
+	}
+
+	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+	rows, rowsErr := treeFrame.rows(app)
+	if rowsErr != nil {
+		return ""
+	}
+
+	title := titleStyle.Render("  Hierarchy")
+	if len(rows) == 0 {
+		return title + marginStyle.Render("No issues\n\n[q] Quit ● [←] Back")
+	}
+
+	// Issues can be deleted from under the cursor
+	treeFrame.cursor = min(treeFrame.cursor, len(rows)-1)
+
+	visibleRows := max(treeFrame.height-9, 1)
+	if treeFrame.cursor < treeFrame.top {
+		treeFrame.top = treeFrame.cursor
+	} else if treeFrame.cursor >= treeFrame.top+visibleRows {
+		treeFrame.top = treeFrame.cursor - visibleRows + 1
+	}
+
+	subtrees := map[string]map[string]bool{}
+	var lines []string
+	for index := treeFrame.top; index < min(treeFrame.top+visibleRows, len(rows)); index++ {
+		row := rows[index]
+		rowCounts := rollUp(app, row.fileName, subtrees)
+
+		marker := "•"
+		if rowCounts.total > 0 && treeFrame.expanded[row.fileName] {
+			marker = "▾"
+		} else if rowCounts.total > 0 {
+			marker = "▸"
+		}
+
+		line := strings.Repeat("  ", row.depth) + marker + " " + row.fileName + " [" + app.Fs.GetFileStatus(row.fileName) + "]"
+		if rowCounts.total > 0 {
+			line += " " + strconv.Itoa(rowCounts.done) + "/" + strconv.Itoa(rowCounts.total) + " done"
+		}
+
+		if index == treeFrame.cursor {
+			lines = append(lines, selectedItemStyle.Render("> "+line))
+			continue
+		}
+
+		lines = append(lines, itemStyle.Render(line))
+	}
+
+	helptext := "[↑/↓] Move ● [space] Expand/collapse ● [v] View File ● [o] Open ● [i] Create child issue ● [r] Delete issue\n[q] Quit ● [←] Back"
+	if treeFrame.errorMessage != "" {
+		helptext = treeFrame.errorMessage + "\n" + helptext
+	}
+
+	return title + "\n\n" + strings.Join(lines, "\n") + marginStyle.Render(helptext)
+}
+
+func (htf HierarchyTreeFrame) Init(app Application) tea.Cmd {
+	return nil
+}
+
+func (htf HierarchyTreeFrame) Refresh(app Application) error {
+	return nil
+}
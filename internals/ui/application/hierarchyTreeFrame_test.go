@@ -0,0 +1,44 @@
+package application
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github/pm/pkg/fileSystem"
+)
+
+func TestRollUpCountsSharedChildrenOnce(t *testing.T) {
+	fs := fileSystem.NewFileSystem(filepath.Join(t.TempDir(), fileSystem.PROJECT_DIRECTORY))
+	if bootErr := fs.Boot(); bootErr != nil {
+		t.Fatal(bootErr)
+	}
+
+	// Login is reached from Launch through both stories
+	steps := []func() error{
+		func() error { return fs.CreateFile("Launch", "epic") },
+		func() error { return fs.CreateFile("Auth", "story") },
+		func() error { return fs.CreateFile("Accounts", "story") },
+		func() error { return fs.CreateFile("Login", "task") },
+		func() error { return fs.LinkHierarchy("Launch", "Auth") },
+		func() error { return fs.LinkHierarchy("Launch", "Accounts") },
+		func() error { return fs.LinkHierarchy("Auth", "Login") },
+		func() error { return fs.LinkHierarchy("Accounts", "Login") },
+		func() error { return fs.SetFileStatus("Login", "done") },
+	}
+
+	for _, step := range steps {
+		if stepErr := step(); stepErr != nil {
+			t.Fatal(stepErr)
+		}
+	}
+
+	app := Application{Fs: fs}
+	subtrees := map[string]map[string]bool{}
+	if counts := rollUp(app, "Launch", subtrees); counts.total != 3 || counts.done != 1 {
+		t.Errorf("Launch rolls up %d issues with %d done, want 3 with 1 done", counts.total, counts.done)
+	}
+
+	if counts := rollUp(app, "Auth", subtrees); counts.total != 1 || counts.done != 1 {
+		t.Errorf("Auth rolls up %d issues with %d done, want 1 with 1 done", counts.total, counts.done)
+	}
+}
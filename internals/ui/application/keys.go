@@ -0,0 +1,42 @@
+package application
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Frames match keys by name, a bound key is replaced by the key message of the name it is bound to
+func remapKey(msg tea.Msg, bindings map[string]string) tea.Msg {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return msg
+	}
+
+	bound, ok := bindings[keyMsg.String()]
+	if !ok {
+		return msg
+	}
+
+	return keyMessage(bound)
+}
+
+// Builds the key message whose String() is name, like "enter", "ctrl+c" or "alt+j"
+func keyMessage(name string) tea.KeyMsg {
+	key := tea.Key{Type: tea.KeyRunes}
+	if strings.HasPrefix(name, "alt+") && name != "alt+" {
+		key.Alt = true
+		name = strings.TrimPrefix(name, "alt+")
+	}
+
+	// Key types are small negative numbers for special keys and ascii control codes
+	for keyType := tea.KeyType(-128); keyType <= 127; keyType++ {
+		if keyType != tea.KeyRunes && keyType.String() == name {
+			key.Type = keyType
+			return tea.KeyMsg(key)
+		}
+	}
+
+	key.Runes = []rune(name)
+	return tea.KeyMsg(key)
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Lets the user pick one of a fixed set of options.
+// The choice is stored on the frame so that it can be read by the previous frame.
+type OptionSelectionFrame struct {
+	options      list.Model
+	selectedItem string
+}
+
+func NewOptionSelectionFrame(title string, options []string) *OptionSelectionFrame {
+	var items []list.Item
+	for _, option := range options {
+		items = append(items, item(option))
+	}
+
+	const defaultWidth = 50
+	l := list.New(items, itemDelegate{}, defaultWidth, len(items)+5)
+	l.Title = title
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.SetShowHelp(false)
+
+	return &OptionSelectionFrame{
+		options: l,
+	}
+}
+
+func (osf OptionSelectionFrame) getFrame(app Application) (*OptionSelectionFrame, error) {
+	frame, error := app.History.Peek()
+	if error != nil {
+		return &OptionSelectionFrame{}, errors.New("Cannot get self")
+	}
+
+	optionFrame := frame.(*OptionSelectionFrame)
+	return optionFrame, nil
+}
+
+func (osf OptionSelectionFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd) {
+	optionFrame, frameErr := osf.getFrame(app)
+	if frameErr != nil {
+		return app, tea.Quit
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return app, tea.Quit
+		case "left":
+			app.History.Pop()
+			return app, nil
+		case "enter":
+			selectedItem, ok := optionFrame.options.SelectedItem().(item)
+			if ok {
+				optionFrame.selectedItem = string(selectedItem)
+			}
+
+			app.History.Pop()
+			return app, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	optionFrame.options, cmd = optionFrame.options.Update(msg)
+	return app, cmd
+}
+
+func (osf OptionSelectionFrame) View(app Application) string {
+	optionFrame, frameErr := osf.getFrame(app)
+	if frameErr != nil {
+		return ""
+	}
+
+	helptext := "[enter] select\n[q] Quit ● [←] Back "
+	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+	return optionFrame.options.View() + marginStyle.Render(helptext)
+}
+
+func (osf OptionSelectionFrame) Init(app Application) tea.Cmd {
+	return nil
+}
+
+func (osf OptionSelectionFrame) Refresh(app Application) error {
+	return nil
+}
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"errors"
+	pmfile "github/pm/pkg/file"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Lists the issues that can be worked on now, see FileSystem.ListReadyIssues
+type ReadyFrame struct {
+	issues list.Model
+}
+
+func readyItems(app Application) ([]list.Item, error) {
+	var issueItems []list.Item
+	ready, readyErr := app.Fs.ListReadyIssues()
+	if readyErr != nil {
+		return issueItems, readyErr
+	}
+
+	for _, issue := range ready {
+		issueItems = append(issueItems, item(issue.Name))
+	}
+
+	return issueItems, nil
+}
+
+func NewReadyFrame(app Application) (*ReadyFrame, error) {
+	issueItems, itemsErr := readyItems(app)
+	if itemsErr != nil {
+		return &ReadyFrame{}, itemsErr
+	}
+
+	const defaultWidth = 50
+	l := list.New(issueItems, itemDelegate{}, defaultWidth, 14)
+	l.Title = "Ready to work on"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.SetShowHelp(false)
+	l.Styles.NoItems = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("240"))
+
+	maxHeight := 9 // Maximum height of the list
+	l.SetHeight(min(len(issueItems)+4, maxHeight))
+
+	return &ReadyFrame{
+		issues: l,
+	}, nil
+}
+
+func (rf ReadyFrame) getFrame(app Application) (*ReadyFrame, error) {
+	frame, error := app.History.Peek()
+	if error != nil {
+		return &ReadyFrame{}, errors.New("Cannot get self")
+	}
+
+	readyFrame := frame.(*ReadyFrame)
+	return readyFrame, nil
+}
+
+func (rf ReadyFrame) Refresh(app Application) error {
+	readyFrame, frameErr := rf.getFrame(app)
+	if frameErr != nil {
+		return frameErr
+	}
+
+	issueItems, itemsErr := readyItems(app)
+	if itemsErr != nil {
+		return itemsErr
+	}
+
+	readyFrame.issues.SetItems(issueItems)
+	selected := readyFrame.issues.SelectedItem()
+	if selected == nil {
+		readyFrame.issues.Select(0)
+	}
+
+	return nil
+}
+
+func (rf ReadyFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd) {
+	readyFrame, frameErr := rf.getFrame(app)
+	if frameErr != nil {
+		return app, tea.Quit
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return app, tea.Quit
+		case "left":
+			app.History.Pop()
+			return app, nil
+		}
+
+		selectedItem, ok := readyFrame.issues.SelectedItem().(item)
+		if !ok {
+			break
+		}
+
+		issueId := string(selectedItem)
+		switch msg.String() {
+		case "v":
+			content, contentErr := app.Fs.RetrieveFileContents(issueId)
+			if contentErr != nil {
+				return app, tea.Quit
+			}
+
+			mdFrame, frameErr := NewViewMarkdownFrame(issueId, content, app)
+			if frameErr != nil {
+				return app, tea.Quit
+			}
+
+			app.History.Push(mdFrame)
+		case "o":
+			app.Fs.EditFile(issueId)
+		case "p":
+			app.Fs.SetFileStatus(issueId, pmfile.FILE_STATUS_IN_PROGRESS)
+		case "x":
+			app.Fs.SetFileStatus(issueId, pmfile.FILE_STATUS_DONE)
+		}
+	}
+
+	var cmd tea.Cmd
+	readyFrame.issues, cmd = readyFrame.issues.Update(msg)
+	return app, cmd
+}
+
+func (rf ReadyFrame) View(app Application) string {
+	readyFrame, frameErr := rf.getFrame(app)
+	if frameErr != nil {
+		return ""
+	}
+
+	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+	if len(readyFrame.issues.Items()) == 0 {
+		return readyFrame.issues.View() + marginStyle.Render("[q] Quit ● [←] Back")
+	}
+
+	helptext := "[v] View File ● [o] Open ● [p] Mark in progress ● [x] Mark done\n[q] Quit ● [←] Back"
+	return readyFrame.issues.View() + marginStyle.Render(helptext)
+}
+
+func (rf ReadyFrame) Init(app Application) tea.Cmd {
+	return nil
+}
@@ -2,6 +2,8 @@ package application
 
 import (
 	"errors"
+	"github/pm/pkg/fileSystem"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
@@ -17,11 +19,14 @@ type ViewMarkdownFrame struct {
 	linkChild      bool
 	linkDownStream bool
 	linkUpsteam    bool
+	moveParent     bool
 	fileType       string
+	errorMessage   string // Why the last link or move failed
 }
 
 // TODO: Need to handle window sizing.
 func NewViewMarkdownFrame(fileName string, content string, app Application) (*ViewMarkdownFrame, error) {
+	content += linkedCommitsMarkdown(app, fileName)
 	str, err := app.Renderer.Render(content)
 	if err != nil {
 		return nil, err
@@ -46,6 +51,32 @@ func NewViewMarkdownFrame(fileName string, content string, app Application) (*Vi
 	}, nil
 }
 
+// Commits linked to the issue by pm scan, listed under the body
+func linkedCommitsMarkdown(app Application, fileName string) string {
+	commits, commitsErr := app.Fs.LinkedCommits(fileName)
+	if commitsErr != nil || len(commits) == 0 {
+		return ""
+	}
+
+	var section strings.Builder
+	section.WriteString("\n\n---\n\n**Commits**\n\n")
+	for _, commit := range commits {
+		hash := commit.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+
+		closes := ""
+		if commit.Closes {
+			closes = " *(closes)*"
+		}
+
+		section.WriteString("- `" + hash + "` " + commit.Subject + closes + "\n")
+	}
+
+	return section.String()
+}
+
 func (vmdf *ViewMarkdownFrame) getFrame(app Application) (*ViewMarkdownFrame, error) {
 	frame, error := app.History.Peek()
 	if error != nil {
@@ -81,18 +112,39 @@ func (vmdf *ViewMarkdownFrame) Update(msg tea.Msg, app Application) (tea.Model,
 		viewMarkdownFrame.subStack.Pop()
 	}
 
+	var actionErr error
 	if viewMarkdownFrame.linkChild && viewMarkdownFrame.selectedItem != "" {
-		app.Fs.LinkHierarchy(viewMarkdownFrame.fileName, viewMarkdownFrame.selectedItem)
+		actionErr = app.Fs.LinkHierarchy(viewMarkdownFrame.fileName, viewMarkdownFrame.selectedItem)
 		viewMarkdownFrame.linkChild = false
 		viewMarkdownFrame.selectedItem = ""
 	} else if viewMarkdownFrame.linkDownStream {
-		app.Fs.LinkDependency(viewMarkdownFrame.fileName, viewMarkdownFrame.selectedItem)
+		if viewMarkdownFrame.selectedItem != "" {
+			actionErr = app.Fs.LinkDependency(viewMarkdownFrame.fileName, viewMarkdownFrame.selectedItem)
+		}
+
 		viewMarkdownFrame.linkDownStream = false
 		viewMarkdownFrame.selectedItem = ""
 	} else if viewMarkdownFrame.linkUpsteam {
-		app.Fs.LinkDependency(viewMarkdownFrame.selectedItem, viewMarkdownFrame.fileName)
+		if viewMarkdownFrame.selectedItem != "" {
+			actionErr = app.Fs.LinkDependency(viewMarkdownFrame.selectedItem, viewMarkdownFrame.fileName)
+		}
+
 		viewMarkdownFrame.linkUpsteam = false
 		viewMarkdownFrame.selectedItem = ""
+	} else if viewMarkdownFrame.moveParent {
+		if viewMarkdownFrame.selectedItem != "" {
+			actionErr = app.Fs.MoveIssue(viewMarkdownFrame.fileName, viewMarkdownFrame.selectedItem)
+		}
+
+		viewMarkdownFrame.moveParent = false
+		viewMarkdownFrame.selectedItem = ""
+	}
+
+	if actionErr != nil {
+		log.Println("Error changing links " + actionErr.Error())
+		viewMarkdownFrame.errorMessage = actionErr.Error()
+	} else if _, isKey := msg.(tea.KeyMsg); isKey {
+		viewMarkdownFrame.errorMessage = ""
 	}
 
 	// Continue operation
@@ -184,6 +236,34 @@ func (vmdf *ViewMarkdownFrame) Update(msg tea.Msg, app Application) (tea.Model,
 			app.History.Push(globalSearchFrame)
 			viewMarkdownFrame.subStack.Push(globalSearchFrame)
 			viewMarkdownFrame.linkUpsteam = true
+		case "m":
+			// Only offer issues that can become the new parent
+			parentTypes := fileSystem.ValidParentTypes(viewMarkdownFrame.fileType)
+			if len(parentTypes) == 0 {
+				return app, nil
+			}
+
+			excludeFiles, parentsErr := app.Fs.ListRelatedParents(viewMarkdownFrame.fileName, fileSystem.FILE_RELATIONSHIPS_HIERARCHY)
+			if parentsErr != nil {
+				log.Println("Error fetching parent issues")
+				return app, tea.Quit
+			}
+
+			globalSearchFrame, frameErr := NewGlobalSelectionFrameOfTypes(app, viewMarkdownFrame.fileName, excludeFiles, parentTypes)
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(globalSearchFrame)
+			viewMarkdownFrame.subStack.Push(globalSearchFrame)
+			viewMarkdownFrame.moveParent = true
+		case "g":
+			graphFrame, frameErr := NewDependencyGraph(viewMarkdownFrame.fileName)
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(graphFrame)
 		case "e":
 			frame := NewBrowseFrame(app, "epic")
 			app.History.Push(frame)
@@ -196,8 +276,12 @@ func (vmdf *ViewMarkdownFrame) Update(msg tea.Msg, app Application) (tea.Model,
 			app.History.Push(frame)
 
 		case "r":
-			app.Fs.DeleteFile(viewMarkdownFrame.fileName, viewMarkdownFrame.fileType)
-			app.History.Pop()
+			deleteFrame, frameErr := NewDeleteIssueFrame(app, viewMarkdownFrame.fileName)
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(deleteFrame)
 		}
 	default:
 		return app, nil
@@ -207,9 +291,13 @@ func (vmdf *ViewMarkdownFrame) Update(msg tea.Msg, app Application) (tea.Model,
 }
 
 func (vmdf *ViewMarkdownFrame) View(app Application) string {
-	helptext := "[o] Open [d] Link Downstream blocker [u] Link Upstream blocker\n[i] Create child issue [r] Delete file\n[q] Quit ● [←] Back\n[e] All epics [s] All stories [t] All tasks"
+	helptext := "[o] Open [d] Link Downstream blocker [u] Link Upstream blocker [g] Dependency graph\n[i] Create child issue [m] Move to parent [r] Delete file\n[q] Quit ● [←] Back\n[e] All epics [s] All stories [t] All tasks"
 	marginStyle := lipgloss.NewStyle().Margin(1, 2)
 
+	if vmdf.errorMessage != "" {
+		helptext = vmdf.errorMessage + "\n" + helptext
+	}
+
 	return app.ViewPort.View() + marginStyle.Render(helptext)
 }
 
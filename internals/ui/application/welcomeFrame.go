@@ -28,6 +28,34 @@ func (wf WelcomeFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd)
 			app.History.Push(frame)
 		case "t":
 			frame := NewBrowseFrame(app, "task")
+			app.History.Push(frame)
+		case "n":
+			frame, frameErr := NewReadyFrame(app)
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(frame)
+		case "h":
+			frame, frameErr := NewHierarchyTreeFrame(app)
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(frame)
+		case "b":
+			frame, frameErr := NewBoardFrame(app)
+			if frameErr != nil {
+				return app, nil
+			}
+
+			app.History.Push(frame)
+		case "w":
+			frame, frameErr := NewWorkspaceFrame(app)
+			if frameErr != nil {
+				return app, nil
+			}
+
 			app.History.Push(frame)
 		}
 	}
@@ -41,7 +69,7 @@ func (wf WelcomeFrame) Refresh(app Application) error {
 
 func (wf WelcomeFrame) View(app Application) string {
 	marginStyle := lipgloss.NewStyle().Margin(1, 2)
-	return marginStyle.Render("Browser\n\n[i] Create issue\n[e] List epics\n[s] List stories\n[t] List tasks\n[q] Quit")
+	return marginStyle.Render("Browser\n\n[i] Create issue\n[e] List epics\n[s] List stories\n[t] List tasks\n[n] Ready to work on\n[h] Hierarchy tree\n[b] Board\n[w] Workspaces\n[q] Quit")
 }
 
 func (wf WelcomeFrame) Init(app Application) tea.Cmd {
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github/pm/pkg/config"
+)
+
+// Lists the registered workspaces. Picking one quits the TUI and pm reopens it in that workspace,
+// so the settings of its project are applied.
+type WorkspaceFrame struct {
+	workspaces []config.Workspace
+	options    list.Model
+}
+
+func NewWorkspaceFrame(app Application) (*WorkspaceFrame, error) {
+	path, pathErr := config.WorkspacesPath()
+	if pathErr != nil {
+		return &WorkspaceFrame{}, pathErr
+	}
+
+	registry, loadErr := config.LoadWorkspaces(path)
+	if loadErr != nil {
+		return &WorkspaceFrame{}, loadErr
+	}
+
+	openRoot, _ := filepath.Abs(app.Fs.Root())
+
+	var items []list.Item
+	for _, workspace := range registry.List {
+		label := workspace.Name
+		if workspace.Root == openRoot {
+			label += " (open)"
+		}
+
+		items = append(items, item(label))
+	}
+
+	const defaultWidth = 50
+	l := list.New(items, itemDelegate{}, defaultWidth, 14)
+	l.Title = "Workspaces"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.SetShowHelp(false)
+	l.Styles.NoItems = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("240"))
+
+	maxHeight := 12
+	l.SetHeight(min(len(items)+5, maxHeight))
+
+	return &WorkspaceFrame{
+		workspaces: registry.List,
+		options:    l,
+	}, nil
+}
+
+func (wf WorkspaceFrame) getFrame(app Application) (*WorkspaceFrame, error) {
+	frame, error := app.History.Peek()
+	if error != nil {
+		return &WorkspaceFrame{}, errors.New("Cannot get self")
+	}
+
+	workspaceFrame := frame.(*WorkspaceFrame)
+	return workspaceFrame, nil
+}
+
+func (wf WorkspaceFrame) Update(msg tea.Msg, app Application) (tea.Model, tea.Cmd) {
+	workspaceFrame, frameErr := wf.getFrame(app)
+	if frameErr != nil {
+		return app, tea.Quit
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return app, tea.Quit
+		case "left":
+			app.History.Pop()
+			return app, nil
+		case "enter":
+			index := workspaceFrame.options.Index()
+			if index < 0 || index >= len(workspaceFrame.workspaces) {
+				return app, nil
+			}
+
+			app.Workspace = workspaceFrame.workspaces[index].Name
+			return app, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	workspaceFrame.options, cmd = workspaceFrame.options.Update(msg)
+	return app, cmd
+}
+
+func (wf WorkspaceFrame) View(app Application) string {
+	workspaceFrame, frameErr := wf.getFrame(app)
+	if frameErr != nil {
+		return ""
+	}
+
+	helptext := "[enter] open\n[q] Quit ● [←] Back "
+	if len(workspaceFrame.workspaces) == 0 {
+		helptext = "Register projects with pm workspace add <name>\n\n[q] Quit ● [←] Back "
+	}
+
+	marginStyle := lipgloss.NewStyle().Margin(1, 2)
+	return workspaceFrame.options.View() + marginStyle.Render(helptext)
+}
+
+func (wf WorkspaceFrame) Init(app Application) tea.Cmd {
+	return nil
+}
+
+func (wf WorkspaceFrame) Refresh(app Application) error {
+	return nil
+}
@@ -13,11 +13,11 @@ import (
 
 const compressionThreshold = 10 * 1024 // 10 KB threshold for compression
 
-// Use Human readable fileNames for easier reading and potability
-func CreateBlob(fileName string, content string) error {
+// Use Human readable fileNames for easier reading and potability.
+// blobDirectory is the blobs directory of the project, such as .pm/blobs
+func CreateBlob(blobDirectory string, fileName string, content string) error {
 	fileName = fileName + ".md"
-	blobDirectory := filepath.Join(".", ".pm", "blobs")
-	blobFile := filepath.Join(".", ".pm", "blobs", fileName)
+	blobFile := filepath.Join(blobDirectory, fileName)
 
 	err := os.MkdirAll(blobDirectory, os.ModePerm)
 	if err != nil {
@@ -44,21 +44,34 @@ func CreateBlob(fileName string, content string) error {
 	return nil
 }
 
+// Replaces the content of an existing blob. Unlike CreateBlob the content is
+// never compressed, blobs are opened in the editor as plain markdown.
+func WriteBlobContent(blobDirectory string, fileName string, content string) error {
+	fileName = fileName + ".md"
+	path := filepath.Join(blobDirectory, fileName)
+	exists := checkFileExists(path)
+	if !exists {
+		return errors.New("Cannot write to non existent file")
+	}
+
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
 func checkFileExists(filePath string) bool {
 	_, error := os.Stat(filePath)
 	//return !os.IsNotExist(err)
 	return !errors.Is(error, os.ErrNotExist)
 }
 
-func Exists(fileName string) bool {
+func Exists(blobDirectory string, fileName string) bool {
 	fileName = fileName + ".md"
-	path := filepath.Join(".", ".pm", "./blobs", fileName)
+	path := filepath.Join(blobDirectory, fileName)
 	return checkFileExists(path)
 }
 
-func ReturnBlobContent(fileName string) (string, error) {
+func ReturnBlobContent(blobDirectory string, fileName string) (string, error) {
 	fileName = fileName + ".md"
-	path := filepath.Join(".", ".pm", "./blobs", fileName)
+	path := filepath.Join(blobDirectory, fileName)
 	exists := checkFileExists(path)
 	if !exists {
 		return "", errors.New("Cannot remove non existent file")
@@ -72,9 +85,9 @@ func ReturnBlobContent(fileName string) (string, error) {
 	return string(content), nil
 }
 
-func DeleteBlob(fileName string) error {
+func DeleteBlob(blobDirectory string, fileName string) error {
 	fileName = fileName + ".md"
-	path := filepath.Join(".", ".pm", "./blobs", fileName)
+	path := filepath.Join(blobDirectory, fileName)
 	exists := checkFileExists(path)
 	if !exists {
 		return errors.New("Cannot remove non existent file")
@@ -88,6 +101,41 @@ func DeleteBlob(fileName string) error {
 	return nil
 }
 
+// Raw content of a blob as it is stored and whether it exists.
+// Used together with RestoreBlob to undo changes to a blob
+func ReadRawBlob(blobDirectory string, fileName string) ([]byte, bool, error) {
+	content, err := os.ReadFile(filepath.Join(blobDirectory, fileName+".md"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return content, true, nil
+}
+
+// Puts back a blob read by ReadRawBlob, removing it when it didn't exist
+func RestoreBlob(blobDirectory string, fileName string, content []byte, existed bool) error {
+	path := filepath.Join(blobDirectory, fileName+".md")
+	if !existed {
+		removeErr := os.Remove(path)
+		if errors.Is(removeErr, os.ErrNotExist) {
+			return nil
+		}
+
+		return removeErr
+	}
+
+	err := os.MkdirAll(blobDirectory, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0644)
+}
+
 func CompressContent(content string) (string, error) {
 	var b bytes.Buffer
 
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"bytes"
 	"encoding/gob"
 	"errors"
 	"log"
@@ -82,12 +83,12 @@ func (r Reconcilable) FastForward(input []Alpha) error {
 	return nil
 }
 
-func (r Reconcilable) SaveReconcilable() {
+func (r Reconcilable) SaveReconcilable() error {
 	file, err := os.Create(r.FilePath)
 	if err != nil {
 		log.Printf(err.Error())
 		log.Println("Error creating file")
-		return
+		return err
 	}
 	defer file.Close()
 
@@ -96,8 +97,30 @@ func (r Reconcilable) SaveReconcilable() {
 	encodingErr := encoder.Encode(r)
 	if encodingErr != nil {
 		log.Println("Error encoding dag", encodingErr.Error())
-		return
+		return encodingErr
 	}
+
+	return file.Close()
+}
+
+// Returns a deep copy of the Reconcilable by passing it through the same
+// encoding that is used to store it on disk
+func (r Reconcilable) Clone() (Reconcilable, error) {
+	var buffer bytes.Buffer
+
+	gob.Register(r.DataStructure)
+	encodingErr := gob.NewEncoder(&buffer).Encode(r)
+	if encodingErr != nil {
+		return Reconcilable{}, encodingErr
+	}
+
+	var clone Reconcilable
+	decodingErr := gob.NewDecoder(&buffer).Decode(&clone)
+	if decodingErr != nil {
+		return Reconcilable{}, decodingErr
+	}
+
+	return clone, nil
 }
 
 func LoadReconcilable(filePath string) *Reconcilable {
@@ -117,6 +140,7 @@ func LoadReconcilable(filePath string) *Reconcilable {
 		return nil
 	}
 
+	loadedReconcilable.FilePath = filePath
 	return loadedReconcilable
 }
 
@@ -9,6 +9,9 @@ const (
 	RemoveTrieNodeAlpha byte = 6
 	AddFileAlpha        byte = 7
 	RemoveFileAlpha     byte = 8
+	SetFileMetaAlpha    byte = 9
+	RemoveFileMetaAlpha byte = 10
+	AddTransitionAlpha  byte = 11
 )
 
 type Alpha interface {
@@ -0,0 +1,445 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/**
+Layered configuration. Values in the project config (.pm/config.toml) override
+the user config (~/.config/pm/config.toml), which overrides the defaults below.
+*/
+
+const SOURCE_DEFAULT = "default"
+const SOURCE_USER = "user"
+const SOURCE_PROJECT = "project"
+
+const CONFIG_FILE = "config.toml"
+
+// Table remapping keys in the TUI, keys.<pressed key> = "<key pm knows>"
+const KEYS_TABLE = "keys"
+
+const SETTING_EDITOR = "editor"
+const SETTING_LOG_FILE = "log.file"
+const SETTING_ISSUE_TYPES = "issue.types"
+const SETTING_GIT_AUTOCOMMIT = "git.autocommit"
+
+type Setting struct {
+	Key         string
+	Default     any
+	Description string
+}
+
+var SETTINGS = []Setting{
+	{SETTING_EDITOR, "", "Command used to edit issue bodies, $EDITOR and then vim when empty"},
+	{SETTING_LOG_FILE, defaultLogPath(), "File the debug log is appended to, logging is off when empty"},
+	{SETTING_ISSUE_TYPES, []any{"epic", "story", "task"}, "Issue types from the top of the hierarchy to the bottom"},
+	{SETTING_GIT_AUTOCOMMIT, "off", "Commit .pm with git, off, change to commit every change or session to commit when pm exits"},
+}
+
+type layer struct {
+	source string
+	path   string
+	values map[string]any
+}
+
+type Config struct {
+	layers []layer // Lowest precedence first
+}
+
+// Entry of List, the effective value of a key and the layer it came from
+type Entry struct {
+	Key    string
+	Value  any
+	Source string
+}
+
+// Location of the user config, $XDG_CONFIG_HOME/pm/config.toml or ~/.config/pm/config.toml
+func UserPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, homeErr := os.UserHomeDir()
+		if homeErr != nil {
+			return "", homeErr
+		}
+
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, "pm", CONFIG_FILE), nil
+}
+
+// $XDG_STATE_HOME/pm/debug.log or ~/.local/state/pm/debug.log, no log without a home directory
+func defaultLogPath() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, homeErr := os.UserHomeDir()
+		if homeErr != nil {
+			return ""
+		}
+
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(stateHome, "pm", "debug.log")
+}
+
+// Location of the project config inside the .pm directory root
+func ProjectPath(root string) string {
+	return filepath.Join(root, CONFIG_FILE)
+}
+
+// Loads the config files that exist, an empty path skips that layer
+func Load(userPath string, projectPath string) (*Config, error) {
+	defaults := map[string]any{}
+	for _, setting := range SETTINGS {
+		defaults[setting.Key] = setting.Default
+	}
+
+	config := &Config{layers: []layer{{source: SOURCE_DEFAULT, values: defaults}}}
+	for _, file := range []layer{{source: SOURCE_USER, path: userPath}, {source: SOURCE_PROJECT, path: projectPath}} {
+		if file.path == "" {
+			continue
+		}
+
+		content, readErr := os.ReadFile(file.path)
+		if errors.Is(readErr, os.ErrNotExist) {
+			continue
+		}
+
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		values, parseErr := parseToml(string(content))
+		if parseErr != nil {
+			return nil, errors.New(file.path + ": " + parseErr.Error())
+		}
+
+		for key, value := range values {
+			validateErr := validateValue(key, value)
+			if validateErr != nil {
+				return nil, errors.New(file.path + ": " + validateErr.Error())
+			}
+		}
+
+		file.values = values
+		config.layers = append(config.layers, file)
+	}
+
+	return config, nil
+}
+
+func findSetting(key string) (Setting, bool) {
+	for _, setting := range SETTINGS {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+
+	return Setting{}, false
+}
+
+// Key bindings are free form, every other key has to be a known setting
+func ValidateKey(key string) error {
+	if strings.HasPrefix(key, KEYS_TABLE+".") && len(key) > len(KEYS_TABLE)+1 {
+		return nil
+	}
+
+	if _, ok := findSetting(key); !ok {
+		keys := []string{}
+		for _, setting := range SETTINGS {
+			keys = append(keys, setting.Key)
+		}
+
+		return errors.New("Unknown config key " + key + ", expected one of " + strings.Join(keys, ", ") + " or " + KEYS_TABLE + ".<key>")
+	}
+
+	return nil
+}
+
+func validateValue(key string, value any) error {
+	keyErr := ValidateKey(key)
+	if keyErr != nil {
+		return keyErr
+	}
+
+	setting, ok := findSetting(key)
+	if !ok {
+		if _, isString := value.(string); !isString {
+			return errors.New(key + " has to be a string")
+		}
+
+		return nil
+	}
+
+	switch setting.Default.(type) {
+	case string:
+		if _, isString := value.(string); !isString {
+			return errors.New(key + " has to be a string")
+		}
+	case []any:
+		list, isList := value.([]any)
+		if !isList {
+			return errors.New(key + " has to be a list of strings")
+		}
+
+		for _, item := range list {
+			if _, isString := item.(string); !isString {
+				return errors.New(key + " has to be a list of strings")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Effective value of key and the layer it came from
+func (config *Config) Get(key string) (any, string, bool) {
+	for index := len(config.layers) - 1; index >= 0; index-- {
+		value, ok := config.layers[index].values[key]
+		if ok {
+			return value, config.layers[index].source, true
+		}
+	}
+
+	return nil, "", false
+}
+
+func (config *Config) GetString(key string) string {
+	value, _, _ := config.Get(key)
+	text, _ := value.(string)
+	return text
+}
+
+func (config *Config) GetStrings(key string) []string {
+	value, _, _ := config.Get(key)
+	list, _ := value.([]any)
+
+	var texts []string
+	for _, item := range list {
+		text, _ := item.(string)
+		texts = append(texts, text)
+	}
+
+	return texts
+}
+
+// Key bindings of every layer merged, pressed key to the key pm knows
+func (config *Config) KeyBindings() map[string]string {
+	bindings := map[string]string{}
+	for _, entry := range config.List() {
+		if strings.HasPrefix(entry.Key, KEYS_TABLE+".") {
+			bindings[strings.TrimPrefix(entry.Key, KEYS_TABLE+".")] = entry.Value.(string)
+		}
+	}
+
+	return bindings
+}
+
+// Every key with its effective value, sorted by key
+func (config *Config) List() []Entry {
+	keys := map[string]bool{}
+	for _, layer := range config.layers {
+		for key := range layer.values {
+			keys[key] = true
+		}
+	}
+
+	entries := []Entry{}
+	for key := range keys {
+		value, source, _ := config.Get(key)
+		entries = append(entries, Entry{Key: key, Value: value, Source: source})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return entries
+}
+
+// Formats a value the way pm config get prints it, lists are comma separated
+func FormatValue(value any) string {
+	if list, isList := value.([]any); isList {
+		items := make([]string, len(list))
+		for index, item := range list {
+			items[index] = FormatValue(item)
+		}
+
+		return strings.Join(items, ",")
+	}
+
+	if text, isString := value.(string); isString {
+		return text
+	}
+
+	return formatToml(value)
+}
+
+// Formats a value as toml, for pm config list
+func FormatToml(value any) string {
+	return formatToml(value)
+}
+
+// Parses a value given on the command line, lists are comma separated
+func ParseValue(key string, raw string) (any, error) {
+	keyErr := ValidateKey(key)
+	if keyErr != nil {
+		return nil, keyErr
+	}
+
+	setting, ok := findSetting(key)
+	if !ok {
+		return raw, nil
+	}
+
+	if _, isList := setting.Default.([]any); isList {
+		list := []any{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+
+		return list, nil
+	}
+
+	return raw, nil
+}
+
+// Sets key in the config file at path, keeping its other lines and comments
+func Set(path string, key string, value any) error {
+	validateErr := validateValue(key, value)
+	if validateErr != nil {
+		return validateErr
+	}
+
+	content, readErr := os.ReadFile(path)
+	if readErr != nil && !errors.Is(readErr, os.ErrNotExist) {
+		return readErr
+	}
+
+	// Refuse to edit a file that doesn't parse, the edit could land in the wrong table
+	_, parseErr := parseToml(string(content))
+	if parseErr != nil {
+		return errors.New(path + ": " + parseErr.Error())
+	}
+
+	updated := setLine(string(content), key, value)
+
+	mkdirErr := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if mkdirErr != nil {
+		return mkdirErr
+	}
+
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// The table a key is written in and its name inside the table
+func splitKey(key string) (string, string) {
+	// Bound keys can contain dots themselves, like keys."."
+	if strings.HasPrefix(key, KEYS_TABLE+".") {
+		return KEYS_TABLE, strings.TrimPrefix(key, KEYS_TABLE+".")
+	}
+
+	index := strings.LastIndex(key, ".")
+	if index == -1 {
+		return "", key
+	}
+
+	return key[:index], key[index+1:]
+}
+
+func formatKey(name string) string {
+	for _, char := range name {
+		if !isBareKeyRune(char) {
+			return quoteToml(name)
+		}
+	}
+
+	return name
+}
+
+func setLine(content string, key string, value any) string {
+	table, name := splitKey(key)
+	line := formatKey(name) + " = " + formatToml(value)
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	currentTable := ""
+	insertAt := -1
+	if table == "" {
+		insertAt = 0
+	}
+
+	for index, raw := range lines {
+		trimmed := strings.TrimSpace(stripComment(raw))
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			keys, _ := parseKey(strings.TrimSpace(trimmed[1 : len(trimmed)-1]))
+			currentTable = strings.Join(keys, ".")
+			if currentTable == table {
+				insertAt = index + 1
+			}
+
+			continue
+		}
+
+		separator := strings.Index(trimmed, "=")
+		if separator == -1 {
+			continue
+		}
+
+		keys, keyErr := parseKey(strings.TrimSpace(trimmed[:separator]))
+		if keyErr != nil {
+			continue
+		}
+
+		// The key may already be written as a dotted key, such as log.file = "pm.log"
+		fullKey := strings.Join(keys, ".")
+		if currentTable != "" {
+			fullKey = currentTable + "." + fullKey
+		}
+
+		if fullKey == key {
+			lines[index] = formatKey(keys[0])
+			for _, part := range keys[1:] {
+				lines[index] += "." + formatKey(part)
+			}
+
+			lines[index] += " = " + formatToml(value)
+			return strings.Join(lines, "\n") + "\n"
+		}
+
+		if currentTable == table {
+			insertAt = index + 1
+		}
+	}
+
+	if insertAt == -1 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+
+		lines = append(lines, "["+strings.Join(tableKeys(table), ".")+"]", line)
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	lines = append(lines[:insertAt], append([]string{line}, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func tableKeys(table string) []string {
+	var keys []string
+	for _, key := range strings.Split(table, ".") {
+		keys = append(keys, formatKey(key))
+	}
+
+	return keys
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path string, content string) {
+	t.Helper()
+
+	writeErr := os.WriteFile(path, []byte(content), 0644)
+	if writeErr != nil {
+		t.Fatal(writeErr)
+	}
+}
+
+func TestProjectOverridesUser(t *testing.T) {
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "user.toml")
+	projectPath := filepath.Join(dir, "project.toml")
+
+	writeConfig(t, userPath, "editor = 'nvim' # comment\n[log]\nfile = \"~/pm.log\"\n")
+	writeConfig(t, projectPath, "log.file = \"\"\n[issue]\ntypes = [\n  \"epic\",\n  \"task\",\n]\n[keys]\n\"ctrl+n\" = \"n\"\n")
+
+	config, loadErr := Load(userPath, projectPath)
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	if editor := config.GetString(SETTING_EDITOR); editor != "nvim" {
+		t.Errorf("editor = %q, want nvim", editor)
+	}
+
+	if _, source, _ := config.Get(SETTING_LOG_FILE); source != SOURCE_PROJECT {
+		t.Errorf("log.file came from %s, want project", source)
+	}
+
+	if types := strings.Join(config.GetStrings(SETTING_ISSUE_TYPES), ","); types != "epic,task" {
+		t.Errorf("issue.types = %s, want epic,task", types)
+	}
+
+	if bound := config.KeyBindings()["ctrl+n"]; bound != "n" {
+		t.Errorf("ctrl+n is bound to %q, want n", bound)
+	}
+}
+
+func TestLoadRejectsUnknownKeysAndTypes(t *testing.T) {
+	dir := t.TempDir()
+	for _, content := range []string{"colour = \"red\"\n", "editor = 3\n", "[issue]\ntypes = \"epic\"\n", "editor = \"vim\"\neditor = \"nano\"\n"} {
+		path := filepath.Join(dir, "config.toml")
+		writeConfig(t, path, content)
+
+		if _, loadErr := Load("", path); loadErr == nil {
+			t.Errorf("expected an error loading %q", content)
+		}
+	}
+}
+
+func TestSetKeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeConfig(t, path, "# pm settings\neditor = \"vim\"\n\n[log]\n# keep the log out of the repo\nfile = \"debug.log\"\n")
+
+	for _, change := range []struct {
+		key   string
+		value any
+	}{
+		{SETTING_EDITOR, "code --wait"},
+		{SETTING_LOG_FILE, "/tmp/pm.log"},
+		{SETTING_ISSUE_TYPES, []any{"epic", "task"}},
+		{KEYS_TABLE + ".ctrl+n", "n"},
+	} {
+		setErr := Set(path, change.key, change.value)
+		if setErr != nil {
+			t.Fatal(setErr)
+		}
+	}
+
+	content, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	want := "# pm settings\neditor = \"code --wait\"\n\n[log]\n# keep the log out of the repo\nfile = \"/tmp/pm.log\"\n\n[issue]\ntypes = [\"epic\", \"task\"]\n\n[keys]\n\"ctrl+n\" = \"n\"\n"
+	if string(content) != want {
+		t.Errorf("config file is\n%s\nwant\n%s", content, want)
+	}
+
+	if _, loadErr := Load("", path); loadErr != nil {
+		t.Error(loadErr)
+	}
+}
+
+func TestWorkspacesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pm", WORKSPACES_FILE)
+
+	workspaces, loadErr := LoadWorkspaces(path)
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	for _, name := range []string{"acme", "client b"} {
+		addErr := workspaces.Add(name, "/work/"+name+"/.pm")
+		if addErr != nil {
+			t.Fatal(addErr)
+		}
+	}
+
+	workspaces.Current = "client b"
+	saveErr := workspaces.Save()
+	if saveErr != nil {
+		t.Fatal(saveErr)
+	}
+
+	loaded, loadErr := LoadWorkspaces(path)
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	if entry, ok := loaded.Find("client b"); !ok || entry.Root != "/work/client b/.pm" || loaded.Current != "client b" {
+		t.Errorf("client b was not saved, got %+v", loaded)
+	}
+
+	if !loaded.Remove("client b") || loaded.Current != "" || len(loaded.List) != 1 {
+		t.Errorf("removing the current workspace left %+v", loaded)
+	}
+}
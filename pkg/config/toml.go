@@ -0,0 +1,310 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/**
+Reads the subset of toml that config files need: tables, bare, quoted and dotted
+keys, strings, numbers, booleans and arrays. Keys are flattened to dotted paths,
+so [log] file = "pm.log" is stored as log.file.
+*/
+
+func parseToml(content string) (map[string]any, error) {
+	values := map[string]any{}
+	table := ""
+
+	lines := strings.Split(content, "\n")
+	for index := 0; index < len(lines); index++ {
+		lineNumber := index + 1
+		line := strings.TrimSpace(stripComment(lines[index]))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, lineError(lineNumber, "unsupported table header "+line)
+			}
+
+			keys, keysErr := parseKey(strings.TrimSpace(line[1 : len(line)-1]))
+			if keysErr != nil {
+				return nil, lineError(lineNumber, keysErr.Error())
+			}
+
+			table = strings.Join(keys, ".")
+			continue
+		}
+
+		separator := strings.Index(line, "=")
+		if separator == -1 {
+			return nil, lineError(lineNumber, "expected key = value")
+		}
+
+		keys, keysErr := parseKey(strings.TrimSpace(line[:separator]))
+		if keysErr != nil {
+			return nil, lineError(lineNumber, keysErr.Error())
+		}
+
+		// Arrays may continue over several lines
+		raw := strings.TrimSpace(line[separator+1:])
+		for strings.HasPrefix(raw, "[") && !arrayClosed(raw) && index+1 < len(lines) {
+			index++
+			raw += " " + strings.TrimSpace(stripComment(lines[index]))
+		}
+
+		value, rest, valueErr := parseValue(raw)
+		if valueErr != nil {
+			return nil, lineError(lineNumber, valueErr.Error())
+		}
+
+		if strings.TrimSpace(rest) != "" {
+			return nil, lineError(lineNumber, "unexpected "+rest)
+		}
+
+		key := strings.Join(keys, ".")
+		if table != "" {
+			key = table + "." + key
+		}
+
+		if _, ok := values[key]; ok {
+			return nil, lineError(lineNumber, "duplicate key "+key)
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+func lineError(lineNumber int, message string) error {
+	return errors.New("line " + strconv.Itoa(lineNumber) + ": " + message)
+}
+
+// Drops a # comment that is not inside a string
+func stripComment(line string) string {
+	var quote byte
+	for index := 0; index < len(line); index++ {
+		switch {
+		case quote == 0 && (line[index] == '"' || line[index] == '\''):
+			quote = line[index]
+		case quote == '"' && line[index] == '\\':
+			index++
+		case quote != 0 && line[index] == quote:
+			quote = 0
+		case quote == 0 && line[index] == '#':
+			return line[:index]
+		}
+	}
+
+	return line
+}
+
+func arrayClosed(raw string) bool {
+	_, _, parseErr := parseValue(raw)
+	return parseErr == nil
+}
+
+// Splits a bare, quoted or dotted key into its parts
+func parseKey(raw string) ([]string, error) {
+	var keys []string
+	for raw != "" {
+		var key string
+		if raw[0] == '"' || raw[0] == '\'' {
+			value, rest, valueErr := parseString(raw)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+
+			key, raw = value, strings.TrimSpace(rest)
+		} else {
+			end := strings.IndexFunc(raw, func(r rune) bool { return !isBareKeyRune(r) })
+			if end == -1 {
+				end = len(raw)
+			}
+
+			key, raw = raw[:end], strings.TrimSpace(raw[end:])
+			if key == "" {
+				return nil, errors.New("invalid key " + raw)
+			}
+		}
+
+		keys = append(keys, key)
+		if raw == "" {
+			break
+		}
+
+		if raw[0] != '.' {
+			return nil, errors.New("invalid key " + raw)
+		}
+
+		raw = strings.TrimSpace(raw[1:])
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("missing key")
+	}
+
+	return keys, nil
+}
+
+func isBareKeyRune(r rune) bool {
+	return r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// Parses the value at the start of raw and returns what follows it
+func parseValue(raw string) (any, string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, "", errors.New("missing value")
+	}
+
+	switch {
+	case raw[0] == '"' || raw[0] == '\'':
+		return parseString(raw)
+	case raw[0] == '[':
+		return parseArray(raw)
+	case strings.HasPrefix(raw, "true"):
+		return true, raw[len("true"):], nil
+	case strings.HasPrefix(raw, "false"):
+		return false, raw[len("false"):], nil
+	}
+
+	end := strings.IndexAny(raw, ", ]\t")
+	if end == -1 {
+		end = len(raw)
+	}
+
+	number := strings.ReplaceAll(raw[:end], "_", "")
+	if integer, intErr := strconv.ParseInt(number, 10, 64); intErr == nil {
+		return integer, raw[end:], nil
+	}
+
+	if float, floatErr := strconv.ParseFloat(number, 64); floatErr == nil {
+		return float, raw[end:], nil
+	}
+
+	return nil, "", errors.New("invalid value " + raw[:end])
+}
+
+func parseString(raw string) (string, string, error) {
+	quote := raw[0]
+	var value strings.Builder
+	for index := 1; index < len(raw); index++ {
+		char := raw[index]
+		if char == quote {
+			return value.String(), raw[index+1:], nil
+		}
+
+		if quote == '\'' || char != '\\' {
+			value.WriteByte(char)
+			continue
+		}
+
+		index++
+		if index == len(raw) {
+			break
+		}
+
+		switch raw[index] {
+		case 'n':
+			value.WriteByte('\n')
+		case 't':
+			value.WriteByte('\t')
+		case 'r':
+			value.WriteByte('\r')
+		case '"', '\\':
+			value.WriteByte(raw[index])
+		case 'u':
+			if index+5 > len(raw) {
+				return "", "", errors.New("invalid escape in " + raw)
+			}
+
+			code, codeErr := strconv.ParseUint(raw[index+1:index+5], 16, 32)
+			if codeErr != nil {
+				return "", "", errors.New("invalid escape in " + raw)
+			}
+
+			value.WriteRune(rune(code))
+			index += 4
+		default:
+			return "", "", errors.New("invalid escape in " + raw)
+		}
+	}
+
+	return "", "", errors.New("unterminated string " + raw)
+}
+
+func parseArray(raw string) ([]any, string, error) {
+	values := []any{}
+	rest := strings.TrimSpace(raw[1:])
+	for {
+		if strings.HasPrefix(rest, "]") {
+			return values, rest[1:], nil
+		}
+
+		value, after, valueErr := parseValue(rest)
+		if valueErr != nil {
+			return nil, "", valueErr
+		}
+
+		values = append(values, value)
+		rest = strings.TrimSpace(after)
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if !strings.HasPrefix(rest, "]") {
+			return nil, "", errors.New("unterminated array " + raw)
+		}
+	}
+}
+
+// Writes value as toml, the inverse of parseValue
+func formatToml(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return quoteToml(typed)
+	case bool:
+		return strconv.FormatBool(typed)
+	case int64:
+		return strconv.FormatInt(typed, 10)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case []any:
+		items := make([]string, len(typed))
+		for index, item := range typed {
+			items[index] = formatToml(item)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	return ""
+}
+
+// Go quoting escapes control characters as \x or \a, which toml doesn't accept
+func quoteToml(value string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for _, char := range value {
+		switch {
+		case char == '"' || char == '\\':
+			quoted.WriteString("\\" + string(char))
+		case char == '\n':
+			quoted.WriteString("\\n")
+		case char == '\t':
+			quoted.WriteString("\\t")
+		case char == '\r':
+			quoted.WriteString("\\r")
+		case char < 0x20 || char == 0x7f:
+			quoted.WriteString(fmt.Sprintf("\\u%04x", char))
+		default:
+			quoted.WriteRune(char)
+		}
+	}
+
+	quoted.WriteByte('"')
+	return quoted.String()
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/**
+Registry of named workspaces, kept next to the user config in workspaces.toml.
+Each workspace maps a name to the .pm directory of a project, the current one
+is opened when pm runs outside of any project.
+*/
+
+const WORKSPACES_FILE = "workspaces.toml"
+const WORKSPACES_TABLE = "workspaces"
+
+type Workspace struct {
+	Name string
+	Root string // Absolute path of the .pm directory
+}
+
+type Workspaces struct {
+	path    string
+	Current string
+	List    []Workspace // Sorted by name
+}
+
+// Location of the workspace registry, next to the user config
+func WorkspacesPath() (string, error) {
+	userPath, userErr := UserPath()
+	if userErr != nil {
+		return "", userErr
+	}
+
+	return filepath.Join(filepath.Dir(userPath), WORKSPACES_FILE), nil
+}
+
+// Loads the registry at path, a missing file is an empty registry
+func LoadWorkspaces(path string) (*Workspaces, error) {
+	workspaces := &Workspaces{path: path}
+
+	content, readErr := os.ReadFile(path)
+	if errors.Is(readErr, os.ErrNotExist) {
+		return workspaces, nil
+	}
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	values, parseErr := parseToml(string(content))
+	if parseErr != nil {
+		return nil, errors.New(path + ": " + parseErr.Error())
+	}
+
+	for key, value := range values {
+		text, isString := value.(string)
+		if !isString {
+			return nil, errors.New(path + ": " + key + " has to be a string")
+		}
+
+		switch {
+		case key == "current":
+			workspaces.Current = text
+		case strings.HasPrefix(key, WORKSPACES_TABLE+"."):
+			workspaces.List = append(workspaces.List, Workspace{Name: strings.TrimPrefix(key, WORKSPACES_TABLE+"."), Root: text})
+		default:
+			return nil, errors.New(path + ": unknown key " + key)
+		}
+	}
+
+	workspaces.sort()
+	return workspaces, nil
+}
+
+func (workspaces *Workspaces) sort() {
+	sort.Slice(workspaces.List, func(i, j int) bool {
+		return workspaces.List[i].Name < workspaces.List[j].Name
+	})
+}
+
+func (workspaces *Workspaces) Find(name string) (Workspace, bool) {
+	for _, workspace := range workspaces.List {
+		if workspace.Name == name {
+			return workspace, true
+		}
+	}
+
+	return Workspace{}, false
+}
+
+// Registers root under name, replacing a workspace of the same name
+func (workspaces *Workspaces) Add(name string, root string) error {
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, "\n\r\t") {
+		return errors.New("Invalid workspace name " + quoteToml(name))
+	}
+
+	for index, workspace := range workspaces.List {
+		if workspace.Name == name {
+			workspaces.List[index].Root = root
+			return nil
+		}
+	}
+
+	workspaces.List = append(workspaces.List, Workspace{Name: name, Root: root})
+	workspaces.sort()
+	return nil
+}
+
+// Removes the workspace, it stops being the current one
+func (workspaces *Workspaces) Remove(name string) bool {
+	for index, workspace := range workspaces.List {
+		if workspace.Name == name {
+			workspaces.List = append(workspaces.List[:index], workspaces.List[index+1:]...)
+			if workspaces.Current == name {
+				workspaces.Current = ""
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// The registry is only written by pm, so it is rewritten as a whole
+func (workspaces *Workspaces) Save() error {
+	var content strings.Builder
+	content.WriteString("# Written by pm workspace\n")
+	if workspaces.Current != "" {
+		content.WriteString("current = " + quoteToml(workspaces.Current) + "\n")
+	}
+
+	content.WriteString("\n[" + WORKSPACES_TABLE + "]\n")
+	for _, workspace := range workspaces.List {
+		content.WriteString(formatKey(workspace.Name) + " = " + quoteToml(workspace.Root) + "\n")
+	}
+
+	mkdirErr := os.MkdirAll(filepath.Dir(workspaces.path), os.ModePerm)
+	if mkdirErr != nil {
+		return mkdirErr
+	}
+
+	return os.WriteFile(workspaces.path, []byte(content.String()), 0644)
+}
@@ -24,11 +24,10 @@ Rewind and Validate are future features for merging strategies
 Not need now
 */
 
-// Provide the storage key to identify instances of dags
-func NewReconcilableDag(storageKey string) common.Reconcilable {
+// Provide the storage key to identify instances of dags and the file they are saved to
+func NewReconcilableDag(storageKey string, filePath string) common.Reconcilable {
 	dagAlphaList := common.NewAlphaList()
 	dagStorage := NewDag(storageKey)
-	filePath := "./.pm/dag/" + storageKey
 
 	return common.Reconcilable{
 		AlphaList:     dagAlphaList,
@@ -432,5 +431,7 @@ func LoadReconcilableDag(filePath string) common.Reconcilable {
 		return common.Reconcilable{}
 	}
 
+	// Projects store the path they were saved to, save back to where it was loaded from instead
+	loadedReconcilable.FilePath = filePath
 	return loadedReconcilable
 }
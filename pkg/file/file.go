@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
 
 	"github/pm/pkg/common"
 )
@@ -27,10 +29,9 @@ type FileTypeIndex struct {
 	FileToType map[string]string
 }
 
-func NewReconcilableFileTypeIndex(storageKey string) common.Reconcilable {
+func NewReconcilableFileTypeIndex(storageKey string, filePath string) common.Reconcilable {
 	fileTypeIndexAlphaList := common.NewAlphaList()
 	indexStorage := NewFileTypeIndex()
-	filePath := "./.pm/fileTypes/" + storageKey
 
 	return common.Reconcilable{
 		AlphaList:     fileTypeIndexAlphaList,
@@ -44,13 +45,37 @@ const FILE_TYPE_EPIC = "epic"
 const FILE_TYPE_STORY = "story"
 const FILE_TYPE_TASK = "task"
 
+// File types ordered from the top of the hierarchy to the bottom
+var FILE_TYPE_HIERARCHY = []string{FILE_TYPE_EPIC, FILE_TYPE_STORY, FILE_TYPE_TASK}
+
+// Replaces the file types with the ones configured for a project, ordered from the top of the hierarchy
+func SetFileTypes(fileTypes []string) error {
+	if len(fileTypes) == 0 {
+		return errors.New("At least one issue type is required")
+	}
+
+	for index, fileType := range fileTypes {
+		if fileType == "" || strings.TrimSpace(fileType) != fileType || strings.ContainsAny(fileType, ",/\\") {
+			return errors.New("Invalid issue type: \"" + fileType + "\"")
+		}
+
+		for _, previous := range fileTypes[:index] {
+			if previous == fileType {
+				return errors.New("Issue type listed twice: " + fileType)
+			}
+		}
+	}
+
+	FILE_TYPE_HIERARCHY = append([]string{}, fileTypes...)
+	return nil
+}
+
 // For mvp, system declares file types
 // TODO: refactor into constants
 func NewFileTypeIndex() *FileTypeIndex {
-	fileTypes := map[string]map[string]string{
-		FILE_TYPE_EPIC:  {},
-		FILE_TYPE_STORY: {},
-		FILE_TYPE_TASK:  {},
+	fileTypes := map[string]map[string]string{}
+	for _, fileType := range FILE_TYPE_HIERARCHY {
+		fileTypes[fileType] = map[string]string{}
 	}
 
 	files := map[string]string{}
@@ -63,7 +88,11 @@ func NewFileTypeIndex() *FileTypeIndex {
 
 func (ft *FileTypeIndex) AddFileToIndex(fileName string, fileType string) error {
 	value, ok := ft.TypeToFile[fileType]
-	if !ok {
+	if !ok && slices.Contains(FILE_TYPE_HIERARCHY, fileType) {
+		// Types configured after the index was created
+		value = map[string]string{}
+		ft.TypeToFile[fileType] = value
+	} else if !ok {
 		return errors.New("File type not found, file not indexed")
 	}
 
@@ -247,5 +276,6 @@ func LoadReconcilableFileTypeIndex(filePath string) common.Reconcilable {
 		return common.Reconcilable{}
 	}
 
+	loadedReconcilable.FilePath = filePath
 	return loadedReconcilable
 }
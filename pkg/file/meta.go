@@ -0,0 +1,259 @@
+package file
+
+import (
+	"crypto/sha1"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github/pm/pkg/common"
+)
+
+/**
+Stores free form key value pairs for every file, such as the status of an issue.
+Files without a value for a key are treated as having the default value
+chosen by the caller.
+
+Status changes are kept as well so the history of an issue can be followed.
+*/
+
+type FileMetaIndex struct {
+	FileToMeta        map[string]map[string]string
+	FileToTransitions map[string][]StatusTransition
+}
+
+// A change of status, At is formatted as RFC 3339
+type StatusTransition struct {
+	From string
+	To   string
+	At   string
+}
+
+const FILE_META_STATUS = "status"
+
+const FILE_STATUS_TODO = "todo"
+const FILE_STATUS_IN_PROGRESS = "in-progress"
+const FILE_STATUS_DONE = "done"
+
+// Statuses ordered in the direction an issue moves through them
+var FILE_STATUSES = []string{FILE_STATUS_TODO, FILE_STATUS_IN_PROGRESS, FILE_STATUS_DONE}
+
+// Estimated amount of work, in whatever unit the project uses
+const FILE_META_ESTIMATE = "estimate"
+
+const FILE_META_PRIORITY = "priority"
+
+const FILE_PRIORITY_HIGH = "high"
+const FILE_PRIORITY_MEDIUM = "medium"
+const FILE_PRIORITY_LOW = "low"
+
+// Priorities ordered from the most to the least urgent
+var FILE_PRIORITIES = []string{FILE_PRIORITY_HIGH, FILE_PRIORITY_MEDIUM, FILE_PRIORITY_LOW}
+
+// Where an imported issue came from, such as github#12 or jira:PROJ-7
+const FILE_META_SOURCE = "source"
+
+func NewReconcilableFileMetaIndex(storageKey string, filePath string) common.Reconcilable {
+	fileMetaIndexAlphaList := common.NewAlphaList()
+	indexStorage := NewFileMetaIndex()
+
+	return common.Reconcilable{
+		AlphaList:     fileMetaIndexAlphaList,
+		DataStructure: indexStorage,
+		FilePath:      filePath,
+	}
+}
+
+func NewFileMetaIndex() *FileMetaIndex {
+	return &FileMetaIndex{
+		FileToMeta:        map[string]map[string]string{},
+		FileToTransitions: map[string][]StatusTransition{},
+	}
+}
+
+func (fm *FileMetaIndex) SetFileMeta(fileName string, key string, value string) error {
+	if fileName == "" || key == "" {
+		return errors.New("File name and key are required to set meta data")
+	}
+
+	meta, ok := fm.FileToMeta[fileName]
+	if !ok {
+		meta = map[string]string{}
+		fm.FileToMeta[fileName] = meta
+	}
+
+	meta[key] = value
+
+	return nil
+}
+
+// Removing meta data of a file without meta data is a no-op
+func (fm *FileMetaIndex) RemoveFileMeta(fileName string) error {
+	delete(fm.FileToMeta, fileName)
+	delete(fm.FileToTransitions, fileName)
+	return nil
+}
+
+func (fm *FileMetaIndex) AddTransition(fileName string, transition StatusTransition) error {
+	if fileName == "" {
+		return errors.New("File name is required to add a transition")
+	}
+
+	// Indexes saved before transitions were recorded have no map yet
+	if fm.FileToTransitions == nil {
+		fm.FileToTransitions = map[string][]StatusTransition{}
+	}
+
+	fm.FileToTransitions[fileName] = append(fm.FileToTransitions[fileName], transition)
+	return nil
+}
+
+// Transitions of a file, oldest first
+func (fm *FileMetaIndex) RetrieveTransitions(fileName string) []StatusTransition {
+	return append([]StatusTransition{}, fm.FileToTransitions[fileName]...)
+}
+
+func (fm *FileMetaIndex) RetrieveFileMeta(fileName string, key string) (string, bool) {
+	meta, ok := fm.FileToMeta[fileName]
+	if !ok {
+		return "", false
+	}
+
+	value, ok := meta[key]
+	return value, ok
+}
+
+func (fm *FileMetaIndex) RetrieveAllFileMeta(fileName string) map[string]string {
+	output := map[string]string{}
+	for key, value := range fm.FileToMeta[fileName] {
+		output[key] = value
+	}
+
+	return output
+}
+
+type SetFileMetaAlpha struct {
+	Hash     string
+	FileName string
+	Key      string
+	Value    string
+}
+
+func (sfm *SetFileMetaAlpha) GetType() byte {
+	return common.SetFileMetaAlpha
+}
+
+func (sfm *SetFileMetaAlpha) GetId() string {
+	return sfm.FileName + sfm.Key + sfm.Value + string(common.SetFileMetaAlpha)
+}
+
+func (sfm *SetFileMetaAlpha) GetHash() string {
+	return sfm.Hash
+}
+
+func (sfm *SetFileMetaAlpha) SetHash(lastAlpha common.Alpha) {
+	prevAlphaHash := lastAlpha.GetHash()
+	currentHash := sha1.Sum([]byte(sfm.GetId() + prevAlphaHash))
+	currentHashStr := fmt.Sprintf("%x", currentHash[:])
+	sfm.Hash = currentHashStr
+}
+
+type RemoveFileMetaAlpha struct {
+	Hash     string
+	FileName string
+}
+
+func (rfm *RemoveFileMetaAlpha) GetType() byte {
+	return common.RemoveFileMetaAlpha
+}
+
+func (rfm *RemoveFileMetaAlpha) GetId() string {
+	return rfm.FileName + string(common.RemoveFileMetaAlpha)
+}
+
+func (rfm *RemoveFileMetaAlpha) GetHash() string {
+	return rfm.Hash
+}
+
+func (rfm *RemoveFileMetaAlpha) SetHash(lastAlpha common.Alpha) {
+	prevAlphaHash := lastAlpha.GetHash()
+	currentHash := sha1.Sum([]byte(rfm.GetId() + prevAlphaHash))
+	currentHashStr := fmt.Sprintf("%x", currentHash[:])
+	rfm.Hash = currentHashStr
+}
+
+type AddTransitionAlpha struct {
+	Hash       string
+	FileName   string
+	Transition StatusTransition
+}
+
+func (ata *AddTransitionAlpha) GetType() byte {
+	return common.AddTransitionAlpha
+}
+
+func (ata *AddTransitionAlpha) GetId() string {
+	return ata.FileName + ata.Transition.From + ata.Transition.To + ata.Transition.At + string(common.AddTransitionAlpha)
+}
+
+func (ata *AddTransitionAlpha) GetHash() string {
+	return ata.Hash
+}
+
+func (ata *AddTransitionAlpha) SetHash(lastAlpha common.Alpha) {
+	prevAlphaHash := lastAlpha.GetHash()
+	currentHash := sha1.Sum([]byte(ata.GetId() + prevAlphaHash))
+	currentHashStr := fmt.Sprintf("%x", currentHash[:])
+	ata.Hash = currentHashStr
+}
+
+func (fm *FileMetaIndex) Update(alpha common.Alpha) error {
+	alphaType := alpha.GetType()
+	var error error
+
+	switch alphaType {
+	case common.SetFileMetaAlpha:
+		setFileMetaAlpha := alpha.(*SetFileMetaAlpha)
+		error = fm.SetFileMeta(setFileMetaAlpha.FileName, setFileMetaAlpha.Key, setFileMetaAlpha.Value)
+	case common.RemoveFileMetaAlpha:
+		removeFileMetaAlpha := alpha.(*RemoveFileMetaAlpha)
+		error = fm.RemoveFileMeta(removeFileMetaAlpha.FileName)
+	case common.AddTransitionAlpha:
+		addTransitionAlpha := alpha.(*AddTransitionAlpha)
+		error = fm.AddTransition(addTransitionAlpha.FileName, addTransitionAlpha.Transition)
+	}
+
+	return error
+}
+
+func (fm *FileMetaIndex) Rewind(alpha common.Alpha) error {
+	return nil
+}
+
+func (fm *FileMetaIndex) Validate(alpha common.Alpha) bool {
+	return true
+}
+
+func LoadReconcilableFileMetaIndex(filePath string) common.Reconcilable {
+	file, fileErr := os.Open(filePath)
+
+	if fileErr != nil {
+		log.Println("Error opening binary file")
+		return common.Reconcilable{}
+	}
+	defer file.Close()
+
+	gob.Register(&FileMetaIndex{})
+	decoder := gob.NewDecoder(file)
+	var loadedReconcilable common.Reconcilable
+	decodingErr := decoder.Decode(&loadedReconcilable)
+	if decodingErr != nil {
+		log.Println("Error decoding", decodingErr.Error())
+		return common.Reconcilable{}
+	}
+
+	loadedReconcilable.FilePath = filePath
+	return loadedReconcilable
+}
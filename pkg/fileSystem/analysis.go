@@ -0,0 +1,186 @@
+package fileSystem
+
+import (
+	pmfile "github/pm/pkg/file"
+
+	"sort"
+	"strconv"
+)
+
+// Issues without an estimate count as one unit of work
+const DEFAULT_ESTIMATE = 1.0
+
+func (fs *FileSystem) SetFileEstimate(fileName string, estimate float64) error {
+	if estimate < 0 {
+		return InvalidError("Estimate cannot be negative")
+	}
+
+	return fs.SetFileMeta(fileName, pmfile.FILE_META_ESTIMATE, strconv.FormatFloat(estimate, 'f', -1, 64))
+}
+
+func (fs *FileSystem) GetFileEstimate(fileName string) float64 {
+	value, ok := fs.GetFileMeta(fileName, pmfile.FILE_META_ESTIMATE)
+	if !ok {
+		return DEFAULT_ESTIMATE
+	}
+
+	estimate, parseErr := strconv.ParseFloat(value, 64)
+	if parseErr != nil {
+		return DEFAULT_ESTIMATE
+	}
+
+	return estimate
+}
+
+// Work left on an issue, done issues have no work left
+func (fs *FileSystem) remainingEstimate(fileName string) float64 {
+	if fs.GetFileStatus(fileName) == pmfile.FILE_STATUS_DONE {
+		return 0
+	}
+
+	return fs.GetFileEstimate(fileName)
+}
+
+// Every issue that is blocked by the issue, directly or through other issues
+func (fs *FileSystem) DownstreamClosure(fileName string) ([]string, error) {
+	return fs.closure(fileName, fs.ListRelatedDependency)
+}
+
+// Every issue that blocks the issue, directly or through other issues
+func (fs *FileSystem) UpstreamClosure(fileName string) ([]string, error) {
+	return fs.closure(fileName, fs.ListRelatedParentDependency)
+}
+
+func (fs *FileSystem) closure(fileName string, next func(string) ([]string, error)) ([]string, error) {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return nil, existsErr
+	}
+
+	visited := map[string]bool{fileName: true}
+	queue := []string{fileName}
+	var reached []string
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		related, relatedErr := next(current)
+		if relatedErr != nil {
+			return nil, relatedErr
+		}
+
+		for _, issue := range related {
+			if visited[issue] {
+				continue
+			}
+
+			visited[issue] = true
+			reached = append(reached, issue)
+			queue = append(queue, issue)
+		}
+	}
+
+	sort.Strings(reached)
+	return reached, nil
+}
+
+// Lists the issue and every issue below it in the hierarchy
+func (fs *FileSystem) HierarchySubtree(fileName string) (map[string]bool, error) {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return nil, existsErr
+	}
+
+	subtree := map[string]bool{}
+	queue := []string{fileName}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if subtree[current] {
+			continue
+		}
+
+		subtree[current] = true
+		children, childrenErr := fs.ListRelatedHierarchy(current)
+		if childrenErr != nil {
+			return nil, childrenErr
+		}
+
+		queue = append(queue, children...)
+	}
+
+	return subtree, nil
+}
+
+// Finds the chain of dependencies with the most remaining estimated work.
+// When root is given only root and the issues below it in the hierarchy are considered.
+// Returns the path from the first upstream issue to the last downstream issue and its total work.
+func (fs *FileSystem) CriticalPath(root string) ([]string, float64, error) {
+	order, _, orderErr := fs.TopologicalDependencyOrder()
+	if orderErr != nil {
+		return nil, 0, orderErr
+	}
+
+	var scope map[string]bool
+	if root != "" {
+		subtree, subtreeErr := fs.HierarchySubtree(root)
+		if subtreeErr != nil {
+			return nil, 0, subtreeErr
+		}
+
+		scope = subtree
+	}
+
+	work := map[string]float64{}
+	previous := map[string]string{}
+	end := ""
+	for _, fileName := range order {
+		if scope != nil && !scope[fileName] {
+			continue
+		}
+
+		upstreams, upstreamErr := fs.ListRelatedParentDependency(fileName)
+		if upstreamErr != nil {
+			return nil, 0, upstreamErr
+		}
+
+		best := ""
+		for _, upstream := range upstreams {
+			_, inScope := work[upstream]
+			if !inScope {
+				continue
+			}
+
+			if best == "" || work[upstream] > work[best] || (work[upstream] == work[best] && upstream < best) {
+				best = upstream
+			}
+		}
+
+		work[fileName] = fs.remainingEstimate(fileName)
+		if best != "" {
+			work[fileName] += work[best]
+			previous[fileName] = best
+		}
+
+		if end == "" || work[fileName] > work[end] || (work[fileName] == work[end] && fileName < end) {
+			end = fileName
+		}
+	}
+
+	if end == "" {
+		return nil, 0, nil
+	}
+
+	path := []string{end}
+	for {
+		upstream, ok := previous[path[0]]
+		if !ok {
+			break
+		}
+
+		path = append([]string{upstream}, path...)
+	}
+
+	return path, work[end], nil
+}
@@ -0,0 +1,74 @@
+package fileSystem
+
+import (
+	"strings"
+	"testing"
+)
+
+// Builds the diamond Schema → Api, Ui → Deploy where the Ui branch carries more work
+func bootDiamond(t *testing.T) *FileSystem {
+	t.Helper()
+
+	fs := bootInTempDir(t)
+	for _, name := range []string{"Schema", "Api", "Ui", "Deploy"} {
+		mustSucceed(t, fs.CreateFile(name, "task"))
+	}
+
+	mustSucceed(t, fs.LinkDependency("Schema", "Api"))
+	mustSucceed(t, fs.LinkDependency("Schema", "Ui"))
+	mustSucceed(t, fs.LinkDependency("Api", "Deploy"))
+	mustSucceed(t, fs.LinkDependency("Ui", "Deploy"))
+	mustSucceed(t, fs.SetFileEstimate("Schema", 2))
+	mustSucceed(t, fs.SetFileEstimate("Api", 3))
+	mustSucceed(t, fs.SetFileEstimate("Ui", 5))
+	mustSucceed(t, fs.SetFileEstimate("Deploy", 0.5))
+
+	return fs
+}
+
+func TestCriticalPathOnWeightedDiamond(t *testing.T) {
+	fs := bootDiamond(t)
+
+	path, work, pathErr := fs.CriticalPath("")
+	mustSucceed(t, pathErr)
+	if strings.Join(path, " → ") != "Schema → Ui → Deploy" || work != 7.5 {
+		t.Errorf("critical path is %s with %v work", strings.Join(path, " → "), work)
+	}
+
+	// Done issues have no work left, so the Api branch becomes the longer one
+	mustSucceed(t, fs.SetFileStatus("Ui", "done"))
+	path, work, pathErr = fs.CriticalPath("")
+	mustSucceed(t, pathErr)
+	if strings.Join(path, " → ") != "Schema → Api → Deploy" || work != 5.5 {
+		t.Errorf("critical path is %s with %v work once Ui is done", strings.Join(path, " → "), work)
+	}
+}
+
+func TestCriticalPathWithinEpic(t *testing.T) {
+	fs := bootDiamond(t)
+	mustSucceed(t, fs.CreateFile("Backend", "epic"))
+	mustSucceed(t, fs.LinkHierarchy("Backend", "Schema"))
+	mustSucceed(t, fs.LinkHierarchy("Backend", "Api"))
+
+	path, work, pathErr := fs.CriticalPath("Backend")
+	mustSucceed(t, pathErr)
+	if strings.Join(path, " → ") != "Schema → Api" || work != 5 {
+		t.Errorf("critical path of Backend is %s with %v work", strings.Join(path, " → "), work)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	fs := bootDiamond(t)
+
+	downstream, downstreamErr := fs.DownstreamClosure("Schema")
+	mustSucceed(t, downstreamErr)
+	if strings.Join(downstream, ", ") != "Api, Deploy, Ui" {
+		t.Errorf("Schema blocks %v", downstream)
+	}
+
+	upstream, upstreamErr := fs.UpstreamClosure("Deploy")
+	mustSucceed(t, upstreamErr)
+	if strings.Join(upstream, ", ") != "Api, Schema, Ui" {
+		t.Errorf("Deploy is blocked by %v", upstream)
+	}
+}
@@ -0,0 +1,176 @@
+package fileSystem
+
+import (
+	"github/pm/pkg/blob"
+	"github/pm/pkg/common"
+	"github/pm/pkg/dag"
+	pmfile "github/pm/pkg/file"
+
+	"errors"
+	"log"
+)
+
+// Copy of everything a FileSystem mutation can touch
+type fileSystemSnapshot struct {
+	fileRelationShips       common.Reconcilable
+	fileTypeIndex           common.Reconcilable
+	fileParentRelationships common.Reconcilable
+	fileMetaIndex           common.Reconcilable
+	blobs                   map[string]blobBackup // Filled as the batch touches blobs
+	journalLength           int
+}
+
+// A blob as it was before a batch first changed it
+type blobBackup struct {
+	content []byte
+	existed bool
+}
+
+func (fs *FileSystem) takeSnapshot() (*fileSystemSnapshot, error) {
+	reconcilables := []common.Reconcilable{
+		fs.fileRelationShips,
+		fs.fileTypeIndex,
+		fs.fileParentRelationships,
+		fs.fileMetaIndex,
+	}
+
+	clones := make([]common.Reconcilable, len(reconcilables))
+	for index, reconcilable := range reconcilables {
+		clone, cloneErr := reconcilable.Clone()
+		if cloneErr != nil {
+			return nil, cloneErr
+		}
+
+		clones[index] = clone
+	}
+
+	return &fileSystemSnapshot{
+		fileRelationShips:       clones[0],
+		fileTypeIndex:           clones[1],
+		fileParentRelationships: clones[2],
+		fileMetaIndex:           clones[3],
+		blobs:                   map[string]blobBackup{},
+		journalLength:           len(fs.journal),
+	}, nil
+}
+
+// Keeps the blob of fileName in every running batch that hasn't changed it yet,
+// called before a blob is written or removed
+func (fs *FileSystem) backupBlob(fileName string) error {
+	for _, snapshot := range fs.batches {
+		if _, ok := snapshot.blobs[fileName]; ok {
+			continue
+		}
+
+		content, existed, readErr := blob.ReadRawBlob(fs.blobDirectory(), fileName)
+		if readErr != nil {
+			return readErr
+		}
+
+		snapshot.blobs[fileName] = blobBackup{content: content, existed: existed}
+	}
+
+	return nil
+}
+
+// Restores the data structures in place, copies of the FileSystem such as
+// the one held by FileGraphRenderer share them and must see the rollback too
+func (fs *FileSystem) restoreSnapshot(snapshot *fileSystemSnapshot) error {
+	*fs.getFileTree() = *snapshot.fileRelationShips.DataStructure.(*dag.Dag)
+	*fs.getParentFileTree() = *snapshot.fileParentRelationships.DataStructure.(*dag.Dag)
+	*fs.getFileIndex() = *snapshot.fileTypeIndex.DataStructure.(*pmfile.FileTypeIndex)
+	*fs.getFileMetaIndex() = *snapshot.fileMetaIndex.DataStructure.(*pmfile.FileMetaIndex)
+	fs.journal = fs.journal[:snapshot.journalLength]
+
+	for fileName, backup := range snapshot.blobs {
+		restoreErr := blob.RestoreBlob(fs.blobDirectory(), fileName, backup.content, backup.existed)
+		if restoreErr != nil {
+			return restoreErr
+		}
+	}
+
+	return nil
+}
+
+// Runs operation as a single batch. If operation fails, every change it
+// made to the file system is rolled back before the error is returned.
+func (fs *FileSystem) Batch(operation func() error) error {
+	snapshot, snapshotErr := fs.takeSnapshot()
+	if snapshotErr != nil {
+		return snapshotErr
+	}
+
+	fs.batches = append(fs.batches, snapshot)
+	operationErr := operation()
+	fs.batches = fs.batches[:len(fs.batches)-1]
+	if operationErr == nil {
+		return nil
+	}
+
+	restoreErr := fs.restoreSnapshot(snapshot)
+	if restoreErr != nil {
+		log.Println("Error rolling back batch " + restoreErr.Error())
+		return errors.Join(operationErr, restoreErr)
+	}
+
+	return operationErr
+}
+
+// Deletes every issue or none of them.
+// Issues already removed by cascading an earlier issue in the batch are skipped.
+func (fs *FileSystem) DeleteIssues(fileNames []string, mode string) error {
+	return fs.Batch(func() error {
+		for _, fileName := range fileNames {
+			if !fs.getFileTree().HasVertex(fileName) {
+				continue
+			}
+
+			deleteErr := fs.deleteIssue(fileName, mode)
+			if deleteErr != nil {
+				return deleteErr
+			}
+		}
+
+		return nil
+	})
+}
+
+func (fs *FileSystem) SetIssuesStatus(fileNames []string, status string) error {
+	return fs.Batch(func() error {
+		for _, fileName := range fileNames {
+			statusErr := fs.SetFileStatus(fileName, status)
+			if statusErr != nil {
+				return statusErr
+			}
+		}
+
+		return nil
+	})
+}
+
+func (fs *FileSystem) MoveIssues(fileNames []string, parentName string) error {
+	return fs.Batch(func() error {
+		for _, fileName := range fileNames {
+			moveErr := fs.MoveIssue(fileName, parentName)
+			if moveErr != nil {
+				return moveErr
+			}
+		}
+
+		return nil
+	})
+}
+
+// Makes every issue depend on the upstream issue
+func (fs *FileSystem) LinkDependencies(upstreamName string, fileNames []string) error {
+	return fs.Batch(func() error {
+		for _, fileName := range fileNames {
+			linkErr := fs.LinkDependency(upstreamName, fileName)
+			if linkErr != nil {
+				return linkErr
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,107 @@
+package fileSystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBatchRollsBackFailedOperation(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Login"))
+	mustSucceed(t, fs.WriteFileContents("Login", "Email and password"))
+	mustSucceed(t, fs.SetFileStatus("Login", "in-progress"))
+
+	// Copies of the FileSystem, like the one FileGraphRenderer holds, share its data structures
+	shared := *fs
+
+	failure := errors.New("failure")
+	batchErr := fs.Batch(func() error {
+		mustSucceed(t, fs.CreateFile("Billing", "story"))
+		mustSucceed(t, fs.MoveIssue("Login", "Billing"))
+		mustSucceed(t, fs.WriteFileContents("Login", "Single sign on"))
+		mustSucceed(t, fs.SetFileStatus("Login", "done"))
+		mustSucceed(t, fs.DeleteFile("Auth", "story"))
+		return failure
+	})
+
+	if !errors.Is(batchErr, failure) {
+		t.Fatalf("Batch returned %v, want the error of the operation", batchErr)
+	}
+
+	for name, view := range map[string]*FileSystem{"file system": fs, "copy": &shared} {
+		if _, typeErr := view.GetFileType("Billing"); typeErr == nil {
+			t.Errorf("%s: Billing is still in the type index", name)
+		}
+
+		if view.getFileTree().HasVertex("Billing") || view.getParentFileTree().HasVertex("Billing") {
+			t.Errorf("%s: Billing is still in the dag", name)
+		}
+
+		if parents, _ := view.ListRelatedParents("Login", FILE_RELATIONSHIPS_HIERARCHY); len(parents) != 1 || parents[0] != "Auth" {
+			t.Errorf("%s: Login is under %v, want Auth", name, parents)
+		}
+
+		if children, _ := view.ListRelatedHierarchy("Auth"); len(children) != 1 || children[0] != "Login" {
+			t.Errorf("%s: Auth has children %v, want Login", name, children)
+		}
+
+		if status := view.GetFileStatus("Login"); status != "in-progress" {
+			t.Errorf("%s: Login is %s, want in-progress", name, status)
+		}
+
+		if transitions := view.GetStatusTransitions("Login"); len(transitions) != 1 {
+			t.Errorf("%s: Login has %d transitions, want 1", name, len(transitions))
+		}
+	}
+
+	if body, _ := fs.RetrieveFileContents("Login"); body != "Email and password" {
+		t.Errorf("Login body is %q after the rollback", body)
+	}
+
+	if existsErr := fs.validateFileExists("Auth"); existsErr != nil {
+		t.Errorf("Auth was not restored: %v", existsErr)
+	}
+
+	if existsErr := fs.validateFileExists("Billing"); existsErr == nil {
+		t.Error("Billing blob was not removed")
+	}
+}
+
+func TestBatchOnlyRestoresTouchedBlobs(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+	mustSucceed(t, fs.WriteFileContents("Login", "Email and password"))
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	untouched := filepath.Join(fs.blobDirectory(), "Auth.md")
+	mustSucceed(t, os.Chtimes(untouched, past, past))
+
+	failure := errors.New("failure")
+	batchErr := fs.Batch(func() error {
+		mustSucceed(t, fs.CreateFile("Billing", "story"))
+		mustSucceed(t, fs.WriteFileContents("Login", "Single sign on"))
+		return failure
+	})
+
+	if !errors.Is(batchErr, failure) {
+		t.Fatalf("Batch returned %v, want the error of the operation", batchErr)
+	}
+
+	if info, statErr := os.Stat(untouched); statErr != nil || !info.ModTime().Equal(past) {
+		t.Errorf("the rollback rewrote the Auth blob: %v", statErr)
+	}
+
+	if body, _ := fs.RetrieveFileContents("Login"); body != "Email and password" {
+		t.Errorf("Login body is %q after the rollback", body)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(fs.blobDirectory(), "Billing.md")); !errors.Is(statErr, os.ErrNotExist) {
+		t.Error("Billing blob was not removed")
+	}
+}
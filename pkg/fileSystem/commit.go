@@ -0,0 +1,231 @@
+package fileSystem
+
+import (
+	"github/pm/pkg/common"
+	"github/pm/pkg/dag"
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/git"
+
+	"strconv"
+	"strings"
+)
+
+// When the .pm directory is committed to git, see SetAutoCommit
+const AUTO_COMMIT_OFF = "off"
+const AUTO_COMMIT_CHANGE = "change"
+const AUTO_COMMIT_SESSION = "session"
+
+var AUTO_COMMIT_MODES = []string{AUTO_COMMIT_OFF, AUTO_COMMIT_CHANGE, AUTO_COMMIT_SESSION}
+
+// Longest subject listing every change, longer summaries move the changes to the body
+const COMMIT_SUBJECT_LENGTH = 72
+
+// A change waiting to be committed, an alpha or a replaced issue body
+type journalEntry struct {
+	alpha common.Alpha
+	body  string
+}
+
+func ValidateAutoCommitMode(mode string) error {
+	if indexOf(AUTO_COMMIT_MODES, mode) == -1 {
+		return InvalidError("Unknown auto commit mode " + mode + ", expected one of " + strings.Join(AUTO_COMMIT_MODES, ", "))
+	}
+
+	return nil
+}
+
+// With change every change is committed on its own, see CommitChange.
+// With session the changes are committed together on ShutDown.
+func (fs *FileSystem) SetAutoCommit(mode string) error {
+	modeErr := ValidateAutoCommitMode(mode)
+	if modeErr != nil {
+		return modeErr
+	}
+
+	if mode != AUTO_COMMIT_OFF {
+		_, repoErr := git.TopLevel(fs.root)
+		if repoErr != nil {
+			return InvalidError("Committing changes needs " + fs.root + " to be inside a git repository: " + repoErr.Error())
+		}
+	}
+
+	fs.autoCommit = mode
+	return nil
+}
+
+// Applies alpha, marks the stores for saving and journals it for the next commit.
+// The parent tree mirrors fileRelationShips and is updated without journaling.
+func (fs *FileSystem) apply(reconcilable common.Reconcilable, alpha common.Alpha) error {
+	updateErr := reconcilable.DataStructure.Update(alpha)
+	if updateErr != nil {
+		return updateErr
+	}
+
+	fs.markDirty(reconcilable)
+
+	if fs.autoCommit != AUTO_COMMIT_OFF {
+		fs.journal = append(fs.journal, journalEntry{alpha: alpha})
+	}
+
+	return nil
+}
+
+func (fs *FileSystem) journalBody(fileName string) {
+	if fs.autoCommit != AUTO_COMMIT_OFF {
+		fs.journal = append(fs.journal, journalEntry{body: fileName})
+	}
+}
+
+// Called after every update of the TUI, commits the changes journaled since the last
+// commit in the change mode. Returns false when there was nothing to commit, a failed
+// commit is only tried again once there are more changes.
+func (fs *FileSystem) CommitChange() (bool, error) {
+	if fs.autoCommit != AUTO_COMMIT_CHANGE || len(fs.journal) == 0 || len(fs.journal) == fs.failedJournal {
+		return false, nil
+	}
+
+	commitErr := fs.Commit()
+	if commitErr != nil {
+		fs.failedJournal = len(fs.journal)
+		return true, commitErr
+	}
+
+	fs.failedJournal = 0
+	return true, nil
+}
+
+// Saves the project and commits the .pm directory with a message summarising the journal
+func (fs *FileSystem) Commit() error {
+	if len(fs.journal) == 0 {
+		return nil
+	}
+
+	saveErr := fs.save()
+	if saveErr != nil {
+		return saveErr
+	}
+
+	_, commitErr := git.CommitDirectory(fs.root, commitMessage(fs.summarizeJournal()))
+	if commitErr != nil {
+		return commitErr
+	}
+
+	fs.journal = nil
+	return nil
+}
+
+func commitMessage(changes []string) string {
+	if len(changes) == 0 {
+		return "pm: update issues"
+	}
+
+	subject := "pm: " + strings.Join(changes, "; ")
+	if len(changes) == 1 || len(subject) <= COMMIT_SUBJECT_LENGTH {
+		return subject
+	}
+
+	var message strings.Builder
+	message.WriteString("pm: " + changes[0] + " and " + strconv.Itoa(len(changes)-1) + " more changes\n\n")
+	for _, change := range changes {
+		message.WriteString("- " + change + "\n")
+	}
+
+	return message.String()
+}
+
+// Describes the journal in words, like create task X under story Y; link X → Z.
+// Edges removed by deleting an issue and the meta and body of new issues are left out.
+func (fs *FileSystem) summarizeJournal() []string {
+	created := map[string]bool{}
+	deleted := map[string]bool{}
+	types := map[string]string{} // Issues deleted since are no longer in the index
+	for _, entry := range fs.journal {
+		switch alpha := entry.alpha.(type) {
+		case *pmfile.AddFileTypeIndexAlpha:
+			created[alpha.FileName] = true
+			types[alpha.FileName] = alpha.FileType
+		case *pmfile.RemoveFileTypeIndexAlpha:
+			deleted[alpha.FileName] = true
+			types[alpha.FileName] = alpha.FileType
+		}
+	}
+
+	describeIssue := func(fileName string) string {
+		fileType, ok := types[fileName]
+		if !ok {
+			fileType, _ = fs.GetFileType(fileName)
+		}
+
+		return strings.TrimSpace(fileType + " " + fileName)
+	}
+
+	// The hierarchy edge added to fileName after index, when it is not described yet
+	consumed := map[int]bool{}
+	nextParent := func(index int, fileName string) string {
+		for later := index + 1; later < len(fs.journal); later++ {
+			edge, ok := fs.journal[later].alpha.(*dag.AddEdgeAlpha)
+			if ok && !consumed[later] && edge.Label == FILE_RELATIONSHIPS_HIERARCHY && edge.To.ID == fileName {
+				consumed[later] = true
+				return edge.From.ID
+			}
+		}
+
+		return ""
+	}
+
+	var changes []string
+	for index, entry := range fs.journal {
+		if consumed[index] {
+			continue
+		}
+
+		change := ""
+		switch alpha := entry.alpha.(type) {
+		case nil:
+			if !created[entry.body] && !deleted[entry.body] {
+				change = "edit " + entry.body
+			}
+		case *pmfile.AddFileTypeIndexAlpha:
+			change = "create " + alpha.FileType + " " + alpha.FileName
+			if parent := nextParent(index, alpha.FileName); parent != "" {
+				change += " under " + describeIssue(parent)
+			}
+		case *pmfile.RemoveFileTypeIndexAlpha:
+			change = "delete " + alpha.FileType + " " + alpha.FileName
+		case *dag.AddEdgeAlpha:
+			if alpha.Label == FILE_RELATIONSHIPS_HIERARCHY {
+				change = "put " + alpha.To.ID + " under " + describeIssue(alpha.From.ID)
+			} else {
+				change = "link " + alpha.From.ID + " → " + alpha.To.ID
+			}
+		case *dag.RemoveEdgeAlpha:
+			if deleted[alpha.From.ID] || deleted[alpha.To.ID] {
+				break
+			}
+
+			if alpha.Label == FILE_RELATIONSHIP_DEPENDENCY {
+				change = "unlink " + alpha.From.ID + " → " + alpha.To.ID
+			} else if parent := nextParent(index, alpha.To.ID); parent != "" {
+				change = "move " + alpha.To.ID + " under " + describeIssue(parent)
+			} else {
+				change = "take " + alpha.To.ID + " out of " + alpha.From.ID
+			}
+		case *pmfile.SetFileMetaAlpha:
+			if created[alpha.FileName] || deleted[alpha.FileName] {
+				break
+			}
+
+			if alpha.Key == pmfile.FILE_META_STATUS {
+				change = "mark " + alpha.FileName + " " + alpha.Value
+			} else {
+				change = "set " + alpha.FileName + " " + alpha.Key + " to " + alpha.Value
+			}
+		}
+
+		if change != "" && (len(changes) == 0 || changes[len(changes)-1] != change) {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,67 @@
+package fileSystem
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarizeJournal(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Billing", "story"))
+	mustSucceed(t, fs.CreateFile("Logout", "task"))
+
+	// Only changes made while auto commits are on are journaled
+	fs.autoCommit = AUTO_COMMIT_SESSION
+	mustSucceed(t, fs.CreateIssue("Login", "task", "Auth", []string{"Logout"}, "Email and password"))
+	mustSucceed(t, fs.MoveIssue("Login", "Billing"))
+	mustSucceed(t, fs.SetFileStatus("Logout", "done"))
+	mustSucceed(t, fs.SetIssueBody("Logout", "Forget the session"))
+	mustSucceed(t, fs.DeleteIssue("Billing", DELETE_MODE_REPARENT))
+
+	// A failed batch leaves nothing in the journal
+	if fs.CreateIssue("Signup", "task", "", []string{"Missing"}, "") == nil {
+		t.Fatal("expected the missing dependency to fail")
+	}
+
+	want := []string{
+		"create task Login under story Auth",
+		"link Logout → Login",
+		"move Login under story Billing",
+		"mark Logout done",
+		"edit Logout",
+		"delete story Billing",
+	}
+
+	if changes := fs.summarizeJournal(); strings.Join(changes, "; ") != strings.Join(want, "; ") {
+		t.Errorf("summary is\n%s\nwant\n%s", strings.Join(changes, "\n"), strings.Join(want, "\n"))
+	}
+
+	if message := commitMessage(want); !strings.HasPrefix(message, "pm: create task Login under story Auth and 5 more changes\n\n- ") {
+		t.Errorf("long summaries should move to the body, got\n%s", message)
+	}
+}
+
+func TestCommitChangeWaitsForChangesAfterAFailure(t *testing.T) {
+	// The temporary directory is not a git repository, so every commit fails
+	fs := bootInTempDir(t)
+	fs.autoCommit = AUTO_COMMIT_CHANGE
+
+	if committed, commitErr := fs.CommitChange(); committed || commitErr != nil {
+		t.Fatalf("committed an empty journal: %v", commitErr)
+	}
+
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	if committed, commitErr := fs.CommitChange(); !committed || commitErr == nil {
+		t.Fatal("expected the commit outside of a repository to fail")
+	}
+
+	if committed, commitErr := fs.CommitChange(); committed || commitErr != nil {
+		t.Errorf("retried the failed commit without new changes: %v", commitErr)
+	}
+
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+	if committed, _ := fs.CommitChange(); !committed {
+		t.Error("did not retry the commit after a new change")
+	}
+}
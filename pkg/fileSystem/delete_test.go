@@ -0,0 +1,87 @@
+package fileSystem
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Launch contains Auth, which contains Login and Logout
+func bootHierarchy(t *testing.T) *FileSystem {
+	t.Helper()
+
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Launch", "epic"))
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+	mustSucceed(t, fs.CreateFile("Logout", "task"))
+	mustSucceed(t, fs.LinkHierarchy("Launch", "Auth"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Login"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Logout"))
+
+	return fs
+}
+
+func issueExists(fs *FileSystem, name string) bool {
+	_, typeErr := fs.GetFileType(name)
+	return typeErr == nil
+}
+
+func TestDeleteRefusesIssuesWithChildren(t *testing.T) {
+	fs := bootHierarchy(t)
+
+	deleteErr := fs.DeleteIssue("Auth", DELETE_MODE_REFUSE)
+	if !errors.Is(deleteErr, ErrConflict) {
+		t.Fatalf("expected a conflict, got %v", deleteErr)
+	}
+
+	for _, name := range []string{"Auth", "Login", "Logout"} {
+		if !issueExists(fs, name) {
+			t.Errorf("refused delete removed %s", name)
+		}
+	}
+
+	mustSucceed(t, fs.DeleteIssue("Login", DELETE_MODE_REFUSE))
+	if children, _ := fs.ListRelatedHierarchy("Auth"); strings.Join(children, ", ") != "Logout" {
+		t.Errorf("Auth still contains %v", children)
+	}
+}
+
+func TestDeleteCascade(t *testing.T) {
+	fs := bootHierarchy(t)
+	mustSucceed(t, fs.DeleteIssue("Auth", DELETE_MODE_CASCADE))
+
+	for _, name := range []string{"Auth", "Login", "Logout"} {
+		if issueExists(fs, name) {
+			t.Errorf("cascade kept %s", name)
+		}
+	}
+
+	if children, _ := fs.ListRelatedHierarchy("Launch"); len(children) != 0 {
+		t.Errorf("Launch still contains %v", children)
+	}
+}
+
+func TestDeleteReparent(t *testing.T) {
+	fs := bootHierarchy(t)
+
+	// Login sits under Launch as well as under Auth
+	mustSucceed(t, fs.LinkHierarchy("Launch", "Login"))
+	mustSucceed(t, fs.DeleteIssue("Auth", DELETE_MODE_REPARENT))
+
+	if issueExists(fs, "Auth") {
+		t.Error("reparent kept Auth")
+	}
+
+	children, childrenErr := fs.ListRelatedHierarchy("Launch")
+	mustSucceed(t, childrenErr)
+	if strings.Join(children, ", ") != "Login, Logout" {
+		t.Errorf("Launch contains %v, want Login and Logout", children)
+	}
+
+	parents, parentsErr := fs.ListRelatedParents("Login", FILE_RELATIONSHIPS_HIERARCHY)
+	mustSucceed(t, parentsErr)
+	if strings.Join(parents, ", ") != "Launch" {
+		t.Errorf("Login is under %v", parents)
+	}
+}
@@ -0,0 +1,299 @@
+package fileSystem
+
+import (
+	pmfile "github/pm/pkg/file"
+
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**
+Concatenates issue blobs into a single markdown document in hierarchy order.
+
+Every issue becomes a numbered section (1, 1.2, 1.2.3) with an html anchor so
+cross references work on GitHub and through pandoc. Headings inside blobs are
+demoted below their section. An issue with several parents is written under
+the first one and referenced from the others.
+*/
+
+type documentSection struct {
+	fileName string
+	number   string
+	depth    int
+	// Set when the issue was already written under another parent
+	reference bool
+}
+
+// Strips the "# <name>" heading issue blobs start with
+func BodyWithoutTitle(body string, fileName string) string {
+	firstLine, rest, _ := strings.Cut(body, "\n")
+	if strings.HasPrefix(firstLine, "# ") && strings.TrimSpace(firstLine[2:]) == fileName {
+		return rest
+	}
+
+	return body
+}
+
+// Orders issues like the hierarchy levels, epics before stories before tasks, then by name
+func (fs *FileSystem) sortByType(fileNames []string) {
+	typeOrder := func(fileName string) int {
+		fileType, typeErr := fs.GetFileType(fileName)
+		if typeErr != nil {
+			return len(pmfile.FILE_TYPE_HIERARCHY)
+		}
+
+		return indexOf(pmfile.FILE_TYPE_HIERARCHY, fileType)
+	}
+
+	sort.Slice(fileNames, func(i, j int) bool {
+		if typeOrder(fileNames[i]) != typeOrder(fileNames[j]) {
+			return typeOrder(fileNames[i]) < typeOrder(fileNames[j])
+		}
+
+		return fileNames[i] < fileNames[j]
+	})
+}
+
+func (fs *FileSystem) documentSections(roots []string) ([]documentSection, error) {
+	var sections []documentSection
+	visited := map[string]bool{}
+
+	var walk func(fileName string, number string, depth int) error
+	walk = func(fileName string, number string, depth int) error {
+		if visited[fileName] {
+			sections = append(sections, documentSection{fileName: fileName, number: number, depth: depth, reference: true})
+			return nil
+		}
+
+		visited[fileName] = true
+		sections = append(sections, documentSection{fileName: fileName, number: number, depth: depth})
+
+		children, childrenErr := fs.ListRelatedHierarchy(fileName)
+		if childrenErr != nil {
+			return childrenErr
+		}
+		fs.sortByType(children)
+
+		for index, child := range children {
+			walkErr := walk(child, number+"."+strconv.Itoa(index+1), depth+1)
+			if walkErr != nil {
+				return walkErr
+			}
+		}
+
+		return nil
+	}
+
+	for index, root := range roots {
+		walkErr := walk(root, strconv.Itoa(index+1), 0)
+		if walkErr != nil {
+			return nil, walkErr
+		}
+	}
+
+	return sections, nil
+}
+
+func sectionAnchor(number string) string {
+	return "req-" + strings.ReplaceAll(number, ".", "-")
+}
+
+// Shifts markdown headings outside of code blocks down by levels
+func demoteHeadings(body string, levels int) string {
+	lines := strings.Split(body, "\n")
+	fence := ""
+	for index, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if fence == "" {
+				fence = trimmed[:3]
+			} else if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+
+			continue
+		}
+
+		if fence != "" || !strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hashes := len(line) - len(strings.TrimLeft(line, "#"))
+		if hashes > 6 || (len(line) > hashes && line[hashes] != ' ') {
+			continue
+		}
+
+		lines[index] = strings.Repeat("#", min(hashes+levels, 6)) + line[hashes:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Writes the requirements below root, or every issue when root is empty, as one markdown document
+func (fs *FileSystem) Document(root string) (string, error) {
+	var roots []string
+	title := "Requirements"
+	if root != "" {
+		existsErr := fs.validateFileExists(root)
+		if existsErr != nil {
+			return "", existsErr
+		}
+
+		roots = []string{root}
+		title = root
+	} else {
+		for fileName := range fs.getFileTree().Vertices {
+			parents, parentsErr := fs.ListRelatedParents(fileName, FILE_RELATIONSHIPS_HIERARCHY)
+			if parentsErr != nil {
+				return "", parentsErr
+			}
+
+			if len(parents) == 0 {
+				roots = append(roots, fileName)
+			}
+		}
+		fs.sortByType(roots)
+	}
+
+	sections, sectionsErr := fs.documentSections(roots)
+	if sectionsErr != nil {
+		return "", sectionsErr
+	}
+
+	numbers := map[string]string{}
+	positions := map[string]int{}
+	for index, section := range sections {
+		if !section.reference {
+			numbers[section.fileName] = section.number
+			positions[section.fileName] = index
+		}
+	}
+
+	link := func(fileName string) string {
+		// Names end up in table cells
+		name := strings.ReplaceAll(fileName, "|", "\\|")
+		number, ok := numbers[fileName]
+		if !ok {
+			return name + " (not in this document)"
+		}
+
+		return "[" + number + " " + name + "](#" + sectionAnchor(number) + ")"
+	}
+
+	links := func(fileNames []string) string {
+		if len(fileNames) == 0 {
+			return "-"
+		}
+
+		sort.Slice(fileNames, func(i, j int) bool {
+			return positions[fileNames[i]] < positions[fileNames[j]]
+		})
+
+		linked := make([]string, len(fileNames))
+		for index, fileName := range fileNames {
+			linked[index] = link(fileName)
+		}
+
+		return strings.Join(linked, ", ")
+	}
+
+	var document strings.Builder
+	document.WriteString("# " + title + "\n\n## Contents\n\n")
+	for _, section := range sections {
+		document.WriteString(strings.Repeat("  ", section.depth) + "- [" + section.number + " " + section.fileName + "](#" + sectionAnchor(section.number) + ")\n")
+	}
+	document.WriteString("\n")
+
+	for _, section := range sections {
+		level := min(section.depth+2, 6)
+		document.WriteString("<a id=\"" + sectionAnchor(section.number) + "\"></a>\n\n")
+		document.WriteString(strings.Repeat("#", level) + " " + section.number + " " + section.fileName + "\n\n")
+
+		if section.reference {
+			document.WriteString("Described in " + link(section.fileName) + ".\n\n")
+			continue
+		}
+
+		fileType, typeErr := fs.GetFileType(section.fileName)
+		if typeErr != nil {
+			return "", typeErr
+		}
+
+		details := []string{"**Type:** " + fileType, "**Status:** " + fs.GetFileStatus(section.fileName)}
+		if priority, ok := fs.GetFileMeta(section.fileName, pmfile.FILE_META_PRIORITY); ok {
+			details = append(details, "**Priority:** "+priority)
+		}
+
+		if estimate, ok := fs.GetFileMeta(section.fileName, pmfile.FILE_META_ESTIMATE); ok {
+			details = append(details, "**Estimate:** "+estimate)
+		}
+
+		document.WriteString(strings.Join(details, " · ") + "\n\n")
+
+		body, bodyErr := fs.RetrieveFileContents(section.fileName)
+		if bodyErr != nil {
+			return "", bodyErr
+		}
+
+		body = strings.TrimSpace(demoteHeadings(BodyWithoutTitle(body, section.fileName), level))
+		if body != "" {
+			document.WriteString(body + "\n\n")
+		}
+
+		upstream, upstreamErr := fs.ListRelatedParents(section.fileName, FILE_RELATIONSHIP_DEPENDENCY)
+		if upstreamErr != nil {
+			return "", upstreamErr
+		}
+
+		downstream, downstreamErr := fs.ListRelatedDependency(section.fileName)
+		if downstreamErr != nil {
+			return "", downstreamErr
+		}
+
+		if len(upstream)+len(downstream) > 0 {
+			document.WriteString("| Dependency | Issue | Status |\n| --- | --- | --- |\n")
+			for _, dependency := range []struct {
+				relation  string
+				fileNames []string
+			}{{"Blocked by", upstream}, {"Blocks", downstream}} {
+				sort.Strings(dependency.fileNames)
+				for _, fileName := range dependency.fileNames {
+					document.WriteString("| " + dependency.relation + " | " + link(fileName) + " | " + fs.GetFileStatus(fileName) + " |\n")
+				}
+			}
+			document.WriteString("\n")
+		}
+	}
+
+	document.WriteString("## Traceability\n\n| Issue | Parents | Children | Blocked by | Blocks |\n| --- | --- | --- | --- | --- |\n")
+	for _, section := range sections {
+		if section.reference {
+			continue
+		}
+
+		parents, parentsErr := fs.ListRelatedParents(section.fileName, FILE_RELATIONSHIPS_HIERARCHY)
+		if parentsErr != nil {
+			return "", parentsErr
+		}
+
+		children, childrenErr := fs.ListRelatedHierarchy(section.fileName)
+		if childrenErr != nil {
+			return "", childrenErr
+		}
+
+		upstream, upstreamErr := fs.ListRelatedParents(section.fileName, FILE_RELATIONSHIP_DEPENDENCY)
+		if upstreamErr != nil {
+			return "", upstreamErr
+		}
+
+		downstream, downstreamErr := fs.ListRelatedDependency(section.fileName)
+		if downstreamErr != nil {
+			return "", downstreamErr
+		}
+
+		document.WriteString("| " + link(section.fileName) + " | " + links(parents) + " | " + links(children) + " | " + links(upstream) + " | " + links(downstream) + " |\n")
+	}
+
+	return document.String(), nil
+}
@@ -0,0 +1,98 @@
+package fileSystem
+
+import (
+	"strings"
+	"testing"
+)
+
+// Launch contains Auth and Billing, which both contain Login, and Login blocks Logout
+func bootDocument(t *testing.T) *FileSystem {
+	t.Helper()
+
+	fs := bootHierarchy(t)
+	mustSucceed(t, fs.CreateFile("Billing", "story"))
+	mustSucceed(t, fs.LinkHierarchy("Launch", "Billing"))
+	mustSucceed(t, fs.LinkHierarchy("Billing", "Login"))
+	mustSucceed(t, fs.LinkDependency("Login", "Logout"))
+	mustSucceed(t, fs.WriteFileContents("Login", "# Login\n\nUsers sign in.\n\n# Fields\n\n```sh\n# not a heading\n```\n"))
+
+	return fs
+}
+
+func TestDocument(t *testing.T) {
+	fs := bootDocument(t)
+
+	document, documentErr := fs.Document("")
+	mustSucceed(t, documentErr)
+
+	expected := "# Requirements\n\n## Contents\n\n" +
+		"- [1 Launch](#req-1)\n" +
+		"  - [1.1 Auth](#req-1-1)\n" +
+		"    - [1.1.1 Login](#req-1-1-1)\n" +
+		"    - [1.1.2 Logout](#req-1-1-2)\n" +
+		"  - [1.2 Billing](#req-1-2)\n" +
+		"    - [1.2.1 Login](#req-1-2-1)\n\n" +
+		"<a id=\"req-1\"></a>\n\n## 1 Launch\n\n**Type:** epic · **Status:** todo\n\n" +
+		"<a id=\"req-1-1\"></a>\n\n### 1.1 Auth\n\n**Type:** story · **Status:** todo\n\n" +
+		"<a id=\"req-1-1-1\"></a>\n\n#### 1.1.1 Login\n\n**Type:** task · **Status:** todo\n\n" +
+		"Users sign in.\n\n##### Fields\n\n```sh\n# not a heading\n```\n\n" +
+		"| Dependency | Issue | Status |\n| --- | --- | --- |\n| Blocks | [1.1.2 Logout](#req-1-1-2) | todo |\n\n" +
+		"<a id=\"req-1-1-2\"></a>\n\n#### 1.1.2 Logout\n\n**Type:** task · **Status:** todo\n\n" +
+		"| Dependency | Issue | Status |\n| --- | --- | --- |\n| Blocked by | [1.1.1 Login](#req-1-1-1) | todo |\n\n" +
+		"<a id=\"req-1-2\"></a>\n\n### 1.2 Billing\n\n**Type:** story · **Status:** todo\n\n" +
+		"<a id=\"req-1-2-1\"></a>\n\n#### 1.2.1 Login\n\nDescribed in [1.1.1 Login](#req-1-1-1).\n\n" +
+		"## Traceability\n\n| Issue | Parents | Children | Blocked by | Blocks |\n| --- | --- | --- | --- | --- |\n" +
+		"| [1 Launch](#req-1) | - | [1.1 Auth](#req-1-1), [1.2 Billing](#req-1-2) | - | - |\n" +
+		"| [1.1 Auth](#req-1-1) | [1 Launch](#req-1) | [1.1.1 Login](#req-1-1-1), [1.1.2 Logout](#req-1-1-2) | - | - |\n" +
+		"| [1.1.1 Login](#req-1-1-1) | [1.1 Auth](#req-1-1), [1.2 Billing](#req-1-2) | - | - | [1.1.2 Logout](#req-1-1-2) |\n" +
+		"| [1.1.2 Logout](#req-1-1-2) | [1.1 Auth](#req-1-1) | - | [1.1.1 Login](#req-1-1-1) | - |\n" +
+		"| [1.2 Billing](#req-1-2) | [1 Launch](#req-1) | [1.1.1 Login](#req-1-1-1) | - | - |\n"
+
+	if document != expected {
+		t.Errorf("document is\n%s\nwant\n%s", document, expected)
+	}
+}
+
+func TestDocumentBelowRoot(t *testing.T) {
+	fs := bootDocument(t)
+
+	document, documentErr := fs.Document("Billing")
+	mustSucceed(t, documentErr)
+
+	for _, want := range []string{
+		"# Billing\n",
+		"<a id=\"req-1-1\"></a>\n\n### 1.1 Login\n",
+		"Users sign in.\n\n#### Fields\n",
+		"| [1.1 Login](#req-1-1) | Auth (not in this document), [1 Billing](#req-1) | - | - | Logout (not in this document) |\n",
+	} {
+		if !strings.Contains(document, want) {
+			t.Errorf("document does not contain %q:\n%s", want, document)
+		}
+	}
+
+	if _, documentErr := fs.Document("Signup"); documentErr == nil {
+		t.Error("expected a document below a missing issue to fail")
+	}
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		levels int
+		output string
+	}{
+		{"headings", "# One\n## Two\ntext # not\n", 2, "### One\n#### Two\ntext # not\n"},
+		{"capped at six", "##### Five\n", 3, "###### Five\n"},
+		{"not headings", "#hashtag\n####### Seven\n", 1, "#hashtag\n####### Seven\n"},
+		{"fenced code", "```\n# comment\n```\n~~~\n# comment\n```\n~~~\n# After\n", 1, "```\n# comment\n```\n~~~\n# comment\n```\n~~~\n## After\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if output := demoteHeadings(test.body, test.levels); output != test.output {
+				t.Errorf("demoted to\n%s\nwant\n%s", output, test.output)
+			}
+		})
+	}
+}
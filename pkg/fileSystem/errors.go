@@ -0,0 +1,40 @@
+package fileSystem
+
+import "errors"
+
+/**
+Kinds of errors callers may want to tell apart, such as the CLI choosing an exit code.
+Errors keep their message and can be matched with errors.Is(err, ErrNotFound).
+*/
+
+var ErrNotFound = errors.New("not found")
+var ErrInvalid = errors.New("invalid")
+var ErrConflict = errors.New("conflict")
+
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (ke kindError) Error() string {
+	return ke.message
+}
+
+func (ke kindError) Is(target error) bool {
+	return target == ke.kind
+}
+
+// An issue or link that doesn't exist
+func NotFoundError(message string) error {
+	return kindError{kind: ErrNotFound, message: message}
+}
+
+// Input that can never be applied, like an unknown type or a bad name
+func InvalidError(message string) error {
+	return kindError{kind: ErrInvalid, message: message}
+}
+
+// Input that clashes with the current project, like an existing issue or a contradictory link
+func ConflictError(message string) error {
+	return kindError{kind: ErrConflict, message: message}
+}
@@ -0,0 +1,280 @@
+package fileSystem
+
+import (
+	pmfile "github/pm/pkg/file"
+
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+/**
+Lossless JSON representation of a whole project, see docs/export.md for the schema.
+
+Issues are sorted by name and edges by from, label and to so exporting the
+same project twice gives the same output.
+*/
+
+const EXPORT_FORMAT_JSON = "json"
+
+// Bumped whenever the schema changes in a way older readers can't handle
+const EXPORT_SCHEMA_VERSION = 1
+
+type ExportedProject struct {
+	Version int             `json:"version"`
+	Issues  []ExportedIssue `json:"issues"`
+	Edges   []ExportedEdge  `json:"edges"`
+}
+
+type ExportedIssue struct {
+	Name        string               `json:"name"`
+	Type        string               `json:"type"`
+	Body        string               `json:"body"`
+	Meta        map[string]string    `json:"meta,omitempty"`
+	Transitions []ExportedTransition `json:"transitions,omitempty"`
+}
+
+type ExportedTransition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	At   string `json:"at"`
+}
+
+type ExportedEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"`
+}
+
+func (fs *FileSystem) Export() (ExportedProject, error) {
+	project := ExportedProject{
+		Version: EXPORT_SCHEMA_VERSION,
+		Issues:  []ExportedIssue{},
+		Edges:   []ExportedEdge{},
+	}
+
+	nodes, edges, graphErr := fs.Subgraph("", FILE_RELATIONSHIPS)
+	if graphErr != nil {
+		return project, graphErr
+	}
+
+	for _, fileName := range nodes {
+		fileType, typeErr := fs.GetFileType(fileName)
+		if typeErr != nil {
+			return project, typeErr
+		}
+
+		body, bodyErr := fs.RetrieveFileContents(fileName)
+		if bodyErr != nil {
+			return project, bodyErr
+		}
+
+		issue := ExportedIssue{
+			Name: fileName,
+			Type: fileType,
+			Body: body,
+		}
+
+		meta := fs.GetAllFileMeta(fileName)
+		if len(meta) > 0 {
+			issue.Meta = meta
+		}
+
+		for _, transition := range fs.GetStatusTransitions(fileName) {
+			issue.Transitions = append(issue.Transitions, ExportedTransition{
+				From: transition.From,
+				To:   transition.To,
+				At:   transition.At,
+			})
+		}
+
+		project.Issues = append(project.Issues, issue)
+	}
+
+	for _, edge := range edges {
+		project.Edges = append(project.Edges, ExportedEdge{
+			From:  edge.From,
+			To:    edge.To,
+			Label: edge.Label,
+		})
+	}
+
+	return project, nil
+}
+
+func (fs *FileSystem) ExportJSON() ([]byte, error) {
+	project, exportErr := fs.Export()
+	if exportErr != nil {
+		return nil, exportErr
+	}
+
+	output, marshalErr := json.MarshalIndent(project, "", "  ")
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+
+	return append(output, '\n'), nil
+}
+
+// Checks the whole project before anything is created so a bad file
+// doesn't leave a half imported project behind
+func (fs *FileSystem) validateImport(project ExportedProject) error {
+	if project.Version != EXPORT_SCHEMA_VERSION {
+		return InvalidError("Unsupported export version " + strconv.Itoa(project.Version) + ", expected " + strconv.Itoa(EXPORT_SCHEMA_VERSION))
+	}
+
+	imported := map[string]bool{}
+	for _, issue := range project.Issues {
+		nameErr := ValidateIssueName(issue.Name)
+		if nameErr != nil {
+			return nameErr
+		}
+
+		if imported[issue.Name] {
+			return InvalidError("Issue listed twice: " + issue.Name)
+		}
+
+		if indexOf(pmfile.FILE_TYPE_HIERARCHY, issue.Type) == -1 {
+			return InvalidError("Unknown type " + issue.Type + " for issue " + issue.Name)
+		}
+
+		if fs.validateFileExists(issue.Name) == nil {
+			return ConflictError("Issue already exists: " + issue.Name)
+		}
+
+		metaErr := validateImportMeta(issue)
+		if metaErr != nil {
+			return metaErr
+		}
+
+		imported[issue.Name] = true
+	}
+
+	for _, edge := range project.Edges {
+		if indexOf(FILE_RELATIONSHIPS, edge.Label) == -1 {
+			return InvalidError("Unknown relationship " + edge.Label + " between " + edge.From + " and " + edge.To)
+		}
+
+		if !imported[edge.From] || !imported[edge.To] {
+			return InvalidError("Edge between unknown issues " + edge.From + " and " + edge.To)
+		}
+	}
+
+	return nil
+}
+
+// Meta values get the same checks as SetFileStatus, SetFilePriority and SetFileEstimate
+func validateImportMeta(issue ExportedIssue) error {
+	if status, ok := issue.Meta[pmfile.FILE_META_STATUS]; ok && indexOf(pmfile.FILE_STATUSES, status) == -1 {
+		return InvalidError("Unknown status " + status + " for issue " + issue.Name)
+	}
+
+	if priority, ok := issue.Meta[pmfile.FILE_META_PRIORITY]; ok && indexOf(pmfile.FILE_PRIORITIES, priority) == -1 {
+		return InvalidError("Unknown priority " + priority + " for issue " + issue.Name)
+	}
+
+	if estimate, ok := issue.Meta[pmfile.FILE_META_ESTIMATE]; ok {
+		work, parseErr := strconv.ParseFloat(estimate, 64)
+		if parseErr != nil || work < 0 {
+			return InvalidError("Estimate has to be a number that isn't negative: " + estimate + " for issue " + issue.Name)
+		}
+	}
+
+	return nil
+}
+
+// Recreates the issues and edges of an export through the regular file system operations.
+// Nothing is imported if any issue or edge can't be.
+func (fs *FileSystem) Import(project ExportedProject) error {
+	validateErr := fs.validateImport(project)
+	if validateErr != nil {
+		return validateErr
+	}
+
+	return fs.Batch(func() error {
+		for _, issue := range project.Issues {
+			createErr := fs.CreateFile(issue.Name, issue.Type)
+			if createErr != nil {
+				return createErr
+			}
+
+			bodyErr := fs.WriteFileContents(issue.Name, issue.Body)
+			if bodyErr != nil {
+				return bodyErr
+			}
+
+			keys := make([]string, 0, len(issue.Meta))
+			for key := range issue.Meta {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				metaErr := fs.SetFileMeta(issue.Name, key, issue.Meta[key])
+				if metaErr != nil {
+					return metaErr
+				}
+			}
+
+			for _, transition := range issue.Transitions {
+				transitionErr := fs.RecordStatusTransition(issue.Name, pmfile.StatusTransition{
+					From: transition.From,
+					To:   transition.To,
+					At:   transition.At,
+				})
+				if transitionErr != nil {
+					return transitionErr
+				}
+			}
+		}
+
+		for _, edge := range project.Edges {
+			linkErr := fs.Link(edge.From, edge.To, edge.Label)
+			if linkErr != nil {
+				return linkErr
+			}
+		}
+
+		return nil
+	})
+}
+
+// Imports the project into a temporary copy of the saved project and returns the error
+// the import would fail with. Nothing in the project directory is written.
+func (fs *FileSystem) DryRunImport(project ExportedProject) error {
+	dir, dirErr := os.MkdirTemp("", "pm-dry-run-")
+	if dirErr != nil {
+		return dirErr
+	}
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, PROJECT_DIRECTORY)
+	copyErr := os.CopyFS(root, os.DirFS(fs.root))
+	if copyErr != nil {
+		return copyErr
+	}
+
+	dryRun := NewFileSystem(root)
+	bootErr := dryRun.Boot()
+	if bootErr != nil {
+		return bootErr
+	}
+
+	return dryRun.Import(project)
+}
+
+func (fs *FileSystem) ImportJSON(data []byte) error {
+	var project ExportedProject
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	decodeErr := decoder.Decode(&project)
+	if decodeErr != nil {
+		return InvalidError("Invalid export file: " + decodeErr.Error())
+	}
+
+	return fs.Import(project)
+}
@@ -0,0 +1,224 @@
+package fileSystem
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Boots a file system in a new temporary project directory
+func bootInTempDir(t *testing.T) *FileSystem {
+	t.Helper()
+
+	workingDir, wdErr := os.Getwd()
+	if wdErr != nil {
+		t.Fatal(wdErr)
+	}
+
+	chdirErr := os.Chdir(t.TempDir())
+	if chdirErr != nil {
+		t.Fatal(chdirErr)
+	}
+
+	t.Cleanup(func() {
+		os.Chdir(workingDir)
+	})
+
+	fs := NewFileSystem(PROJECT_DIRECTORY)
+	bootErr := fs.Boot()
+	if bootErr != nil {
+		t.Fatal(bootErr)
+	}
+
+	return fs
+}
+
+func mustSucceed(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source := bootInTempDir(t)
+	mustSucceed(t, source.CreateFile("Launch", "epic"))
+	mustSucceed(t, source.CreateFile("Auth", "story"))
+	mustSucceed(t, source.CreateFile("Login form", "task"))
+	mustSucceed(t, source.CreateFile("Sessions", "task"))
+	mustSucceed(t, source.LinkHierarchy("Launch", "Auth"))
+	mustSucceed(t, source.LinkHierarchy("Auth", "Login form"))
+	mustSucceed(t, source.LinkHierarchy("Auth", "Sessions"))
+	mustSucceed(t, source.LinkDependency("Sessions", "Login form"))
+	mustSucceed(t, source.WriteFileContents("Login form", "# Login form\n\n\"Email\" and password\n"))
+	mustSucceed(t, source.SetFileStatus("Sessions", "in-progress"))
+	mustSucceed(t, source.SetFileStatus("Sessions", "done"))
+	mustSucceed(t, source.SetFileEstimate("Login form", 2.5))
+	mustSucceed(t, source.SetFilePriority("Auth", "high"))
+
+	exported, exportErr := source.ExportJSON()
+	mustSucceed(t, exportErr)
+
+	target := bootInTempDir(t)
+	mustSucceed(t, target.ImportJSON(exported))
+
+	reexported, reexportErr := target.ExportJSON()
+	mustSucceed(t, reexportErr)
+
+	if !bytes.Equal(exported, reexported) {
+		t.Fatalf("Export changed after import\nbefore:\n%s\nafter:\n%s", exported, reexported)
+	}
+
+	// The import has to survive a restart like any other change
+	mustSucceed(t, target.ShutDown())
+	rebooted := NewFileSystem(PROJECT_DIRECTORY)
+	mustSucceed(t, rebooted.Boot())
+
+	rebootedExport, rebootedErr := rebooted.ExportJSON()
+	mustSucceed(t, rebootedErr)
+
+	if !bytes.Equal(exported, rebootedExport) {
+		t.Fatalf("Export changed after restart\nbefore:\n%s\nafter:\n%s", exported, rebootedExport)
+	}
+}
+
+func TestImportIsAtomic(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Existing", "task"))
+
+	before, beforeErr := fs.ExportJSON()
+	mustSucceed(t, beforeErr)
+
+	project := ExportedProject{
+		Version: EXPORT_SCHEMA_VERSION,
+		Issues: []ExportedIssue{
+			{Name: "Epic", Type: "epic"},
+			{Name: "Task", Type: "task"},
+		},
+		Edges: []ExportedEdge{
+			{From: "Epic", To: "Task", Label: FILE_RELATIONSHIPS_HIERARCHY},
+			// A task can't depend on its own epic
+			{From: "Task", To: "Epic", Label: FILE_RELATIONSHIP_DEPENDENCY},
+		},
+	}
+
+	if fs.Import(project) == nil {
+		t.Fatal("Expected contradictory import to fail")
+	}
+
+	project.Edges = nil
+	project.Issues = append(project.Issues, ExportedIssue{Name: "Existing", Type: "task"})
+	if fs.Import(project) == nil {
+		t.Fatal("Expected import of an existing issue to fail")
+	}
+
+	after, afterErr := fs.ExportJSON()
+	mustSucceed(t, afterErr)
+
+	if !bytes.Equal(before, after) {
+		t.Fatalf("Failed import changed the project\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+}
+
+func TestImportRejectsOtherVersions(t *testing.T) {
+	fs := bootInTempDir(t)
+
+	importErr := fs.ImportJSON([]byte(`{"version": 2, "issues": [], "edges": []}`))
+	if importErr == nil {
+		t.Fatal("Expected import of an unknown version to fail")
+	}
+}
+
+func TestImportValidatesIssues(t *testing.T) {
+	fs := bootInTempDir(t)
+
+	projects := map[string]ExportedProject{
+		"name escaping the blobs": {Issues: []ExportedIssue{{Name: "../../escaped", Type: "task"}}},
+		"unknown status":          {Issues: []ExportedIssue{{Name: "Login", Type: "task", Meta: map[string]string{"status": "bogus"}}}},
+		"unknown priority":        {Issues: []ExportedIssue{{Name: "Login", Type: "task", Meta: map[string]string{"priority": "urgent"}}}},
+		"estimate not a number":   {Issues: []ExportedIssue{{Name: "Login", Type: "task", Meta: map[string]string{"estimate": "two"}}}},
+		"story under a task": {
+			Issues: []ExportedIssue{{Name: "Login", Type: "task"}, {Name: "Auth", Type: "story"}},
+			Edges:  []ExportedEdge{{From: "Login", To: "Signup", Label: FILE_RELATIONSHIPS_HIERARCHY}},
+		},
+	}
+
+	for name, project := range projects {
+		project.Version = EXPORT_SCHEMA_VERSION
+		if importErr := fs.Import(project); !errors.Is(importErr, ErrInvalid) {
+			t.Errorf("%s: expected the import to be invalid, got %v", name, importErr)
+		}
+	}
+
+	files, _ := fs.ListAllFilesWithTypes()
+	for fileType, fileNames := range files {
+		if len(fileNames) > 0 {
+			t.Errorf("rejected imports created %s %v", fileType, fileNames)
+		}
+	}
+
+	if _, statErr := os.Stat("escaped.md"); statErr == nil {
+		t.Error("the import wrote outside of the blobs directory")
+	}
+}
+
+// Modification times of every file in the project directory
+func projectModTimes(t *testing.T, fs *FileSystem) map[string]time.Time {
+	t.Helper()
+
+	modTimes := map[string]time.Time{}
+	walkErr := filepath.WalkDir(fs.root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return infoErr
+		}
+
+		modTimes[path] = info.ModTime()
+		return nil
+	})
+	mustSucceed(t, walkErr)
+
+	return modTimes
+}
+
+func TestDryRunImportLeavesTheProjectAlone(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.ShutDown())
+	before := projectModTimes(t, fs)
+
+	project := ExportedProject{
+		Version: EXPORT_SCHEMA_VERSION,
+		Issues:  []ExportedIssue{{Name: "Signup", Type: "story"}, {Name: "Login", Type: "task"}},
+		Edges:   []ExportedEdge{{From: "Signup", To: "Login", Label: FILE_RELATIONSHIPS_HIERARCHY}},
+	}
+	mustSucceed(t, fs.DryRunImport(project))
+
+	project.Edges = []ExportedEdge{{From: "Login", To: "Signup", Label: FILE_RELATIONSHIPS_HIERARCHY}}
+	if dryRunErr := fs.DryRunImport(project); !errors.Is(dryRunErr, ErrInvalid) {
+		t.Errorf("expected the dry run to be invalid, got %v", dryRunErr)
+	}
+
+	after := projectModTimes(t, fs)
+	if len(after) != len(before) {
+		t.Errorf("the dry run changed the project files from %v to %v", before, after)
+	}
+
+	for path, modTime := range before {
+		if !after[path].Equal(modTime) {
+			t.Errorf("the dry run wrote %s", path)
+		}
+	}
+
+	if issueExists(fs, "Login") {
+		t.Error("the dry run created Login")
+	}
+}
@@ -11,21 +11,51 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const FILE_RELATIONSHIP_DEPENDENCY = "DEPENDENCY"
 const FILE_RELATIONSHIPS_HIERARCHY = "HIERARCHY"
 
+// Decides what happens to the hierarchy children of an issue being deleted
+const DELETE_MODE_REFUSE = "refuse"
+const DELETE_MODE_CASCADE = "cascade"
+const DELETE_MODE_REPARENT = "reparent"
+
 type FileSystem struct {
+	root                    string                // The .pm directory of the project
+	editor                  string                // Overrides $EDITOR when set
+	autoCommit              string                // One of AUTO_COMMIT_MODES
+	journal                 []journalEntry        // Changes since the last commit, kept unless autoCommit is off
+	failedJournal           int                   // Length of the journal when CommitChange last failed
+	dirty                   map[string]bool       // Paths of the stores changed since they were last saved
+	batches                 []*fileSystemSnapshot // Running batches, innermost last
 	fileRelationShips       common.Reconcilable
 	fileTypeIndex           common.Reconcilable
 	fileParentRelationships common.Reconcilable
+	fileMetaIndex           common.Reconcilable
+}
+
+// root is the .pm directory of the project, see FindProjectRoot
+func NewFileSystem(root string) *FileSystem {
+	return &FileSystem{root: root, autoCommit: AUTO_COMMIT_OFF, dirty: map[string]bool{}}
+}
+
+func (fs *FileSystem) Root() string {
+	return fs.root
+}
+
+// Command EditFile opens issues with, $EDITOR is used when it is empty
+func (fs *FileSystem) SetEditor(editor string) {
+	fs.editor = editor
 }
 
-func NewFileSystem() *FileSystem {
-	return &FileSystem{}
+func (fs *FileSystem) blobDirectory() string {
+	return filepath.Join(fs.root, "blobs")
 }
 
 func checkFileExists(filePath string) bool {
@@ -39,16 +69,58 @@ func checkDirExists(dirPath string) bool {
 	return !os.IsNotExist(err)
 }
 
+// Gob doesn't encode maps in a stable order, only the stores that changed are written
+// so that reading a project committed to git doesn't modify it
+// Stores that fail to save stay dirty so the next save retries them
+func (fs *FileSystem) save() error {
+	var saveErr error
+	for _, reconcilable := range []common.Reconcilable{fs.fileRelationShips, fs.fileParentRelationships, fs.fileTypeIndex, fs.fileMetaIndex} {
+		if !fs.dirty[reconcilable.FilePath] {
+			continue
+		}
+
+		storeErr := reconcilable.SaveReconcilable()
+		if storeErr != nil {
+			saveErr = errors.Join(saveErr, errors.New("Error saving "+reconcilable.FilePath+": "+storeErr.Error()))
+			continue
+		}
+
+		delete(fs.dirty, reconcilable.FilePath)
+	}
+
+	return saveErr
+}
+
+// The parent tree mirrors fileRelationShips and changes with it
+func (fs *FileSystem) markDirty(reconcilable common.Reconcilable) {
+	fs.dirty[reconcilable.FilePath] = true
+	if reconcilable.FilePath == fs.fileRelationShips.FilePath {
+		fs.dirty[fs.fileParentRelationships.FilePath] = true
+	}
+}
+
+// Saves the project, and commits it unless auto commits are off
 func (fs *FileSystem) ShutDown() error {
-	fs.fileRelationShips.SaveReconcilable()
-	fs.fileParentRelationships.SaveReconcilable()
-	fs.fileTypeIndex.SaveReconcilable()
-	return nil
+	saveErr := fs.save()
+	if saveErr != nil {
+		return saveErr
+	}
+
+	if fs.autoCommit == AUTO_COMMIT_OFF {
+		return nil
+	}
+
+	commitErr := fs.Commit()
+	if commitErr != nil {
+		log.Println("Error committing changes " + commitErr.Error())
+	}
+
+	return commitErr
 }
 
 func (fs *FileSystem) BootDag(key string) (common.Reconcilable, error) {
-	dagDirectory := filepath.Join(".", ".pm", "dag")
-	dagFile := filepath.Join(".", ".pm", "dag", key)
+	dagDirectory := filepath.Join(fs.root, "dag")
+	dagFile := filepath.Join(dagDirectory, key)
 
 	if !checkDirExists(dagDirectory) {
 		err := os.MkdirAll(dagDirectory, os.ModePerm)
@@ -66,15 +138,16 @@ func (fs *FileSystem) BootDag(key string) (common.Reconcilable, error) {
 		defer file.Close()
 
 		// TODO: Refactor to pass in path file
-		return dag.NewReconcilableDag(key), nil
+		fs.dirty[dagFile] = true
+		return dag.NewReconcilableDag(key, dagFile), nil
 	} else {
 		return dag.LoadReconcilableDag(dagFile), nil
 	}
 }
 
 func (fs *FileSystem) BootFileTypes() error {
-	fileTypeDirectory := filepath.Join(".", ".pm", "fileTypes")
-	fileTypeFile := filepath.Join(".", ".pm", "fileTypes", "types")
+	fileTypeDirectory := filepath.Join(fs.root, "fileTypes")
+	fileTypeFile := filepath.Join(fileTypeDirectory, "types")
 
 	if !checkDirExists(fileTypeDirectory) {
 		err := os.MkdirAll(fileTypeDirectory, os.ModePerm)
@@ -92,12 +165,32 @@ func (fs *FileSystem) BootFileTypes() error {
 
 		defer file.Close()
 
-		fs.fileTypeIndex = pmfile.NewReconcilableFileTypeIndex("types")
-		defer fs.fileTypeIndex.SaveReconcilable()
+		fs.fileTypeIndex = pmfile.NewReconcilableFileTypeIndex("types", fileTypeFile)
+		fs.markDirty(fs.fileTypeIndex)
 	} else {
 		fileTypeIndex := pmfile.LoadReconcilableFileTypeIndex(fileTypeFile)
 		fs.fileTypeIndex = fileTypeIndex
-		defer fs.fileTypeIndex.SaveReconcilable()
+	}
+
+	return nil
+}
+
+func (fs *FileSystem) BootFileMeta() error {
+	fileMetaDirectory := filepath.Join(fs.root, "fileMeta")
+	fileMetaFile := filepath.Join(fileMetaDirectory, "meta")
+
+	if !checkDirExists(fileMetaDirectory) {
+		err := os.MkdirAll(fileMetaDirectory, os.ModePerm)
+		if err != nil {
+			return errors.New("Error creating directory for file meta data")
+		}
+	}
+
+	if !checkFileExists(fileMetaFile) {
+		fs.fileMetaIndex = pmfile.NewReconcilableFileMetaIndex("meta", fileMetaFile)
+		fs.markDirty(fs.fileMetaIndex)
+	} else {
+		fs.fileMetaIndex = pmfile.LoadReconcilableFileMetaIndex(fileMetaFile)
 	}
 
 	return nil
@@ -111,7 +204,6 @@ func (fs *FileSystem) Boot() error {
 	}
 
 	fs.fileRelationShips = childDag
-	defer fs.fileRelationShips.SaveReconcilable()
 
 	parentDag, bootParentDagErr := fs.BootDag("parent")
 	if bootParentDagErr != nil {
@@ -119,7 +211,6 @@ func (fs *FileSystem) Boot() error {
 	}
 
 	fs.fileParentRelationships = parentDag
-	defer fs.fileRelationShips.SaveReconcilable()
 
 	// Load/CreateFile Type index
 	bootIndexErr := fs.BootFileTypes()
@@ -127,13 +218,23 @@ func (fs *FileSystem) Boot() error {
 		return bootIndexErr
 	}
 
-	return nil
+	bootMetaErr := fs.BootFileMeta()
+	if bootMetaErr != nil {
+		return bootMetaErr
+	}
+
+	// Stores created by booting a new project are written right away
+	return fs.save()
 }
 
 func (fs *FileSystem) getFileIndex() *pmfile.FileTypeIndex {
 	return fs.fileTypeIndex.DataStructure.(*pmfile.FileTypeIndex)
 }
 
+func (fs *FileSystem) getFileMetaIndex() *pmfile.FileMetaIndex {
+	return fs.fileMetaIndex.DataStructure.(*pmfile.FileMetaIndex)
+}
+
 func (fs *FileSystem) getFileTree() *dag.Dag {
 	return fs.fileRelationShips.DataStructure.(*dag.Dag)
 }
@@ -146,7 +247,12 @@ func (fs *FileSystem) CreateFile(fileName string, fileType string) error {
 	log.Println("Filename: " + fileName + " created")
 	// Create Blob using fileName
 	// TODO: refactor to use reconcilable data structure
-	blobErr := blob.CreateBlob(fileName, "")
+	backupErr := fs.backupBlob(fileName)
+	if backupErr != nil {
+		return backupErr
+	}
+
+	blobErr := blob.CreateBlob(fs.blobDirectory(), fileName, "")
 	if blobErr != nil {
 		return blobErr
 	}
@@ -157,7 +263,7 @@ func (fs *FileSystem) CreateFile(fileName string, fileType string) error {
 		FileType: fileType,
 	}
 
-	updateErr := fs.fileTypeIndex.DataStructure.Update(&addFileIndexAlpha)
+	updateErr := fs.apply(fs.fileTypeIndex, &addFileIndexAlpha)
 	if updateErr != nil {
 		return updateErr
 	}
@@ -169,7 +275,7 @@ func (fs *FileSystem) CreateFile(fileName string, fileType string) error {
 		Target: vertex,
 	}
 
-	updateErr = fs.fileRelationShips.DataStructure.Update(&addVertexAlpha)
+	updateErr = fs.apply(fs.fileRelationShips, &addVertexAlpha)
 	if updateErr != nil {
 		return updateErr
 	}
@@ -187,24 +293,35 @@ func (fs *FileSystem) CreateFile(fileName string, fileType string) error {
 	return nil
 }
 
-func (FileSystem) EditFile(fileName string) {
-	filePath := filepath.Join(".", ".pm", "./blobs", fileName+".md")
-	editor := os.Getenv("EDITOR")
+func (fs *FileSystem) EditFile(fileName string) {
+	filePath := filepath.Join(fs.blobDirectory(), fileName+".md")
+	editor := fs.editor
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
 	if editor == "" {
 		// Fallback to a default editor if $EDITOR is not set
 		editor = "vim"
 	}
 
+	before, _ := fs.RetrieveFileContents(fileName)
+
 	// Open the file in the editor
 	err := openEditor(editor, filePath)
 	if err != nil {
 		return
 	}
+
+	if after, _ := fs.RetrieveFileContents(fileName); after != before {
+		fs.journalBody(fileName)
+	}
 }
 
 func openEditor(editor string, filePath string) error {
-	// Create an exec command to open the file in the editor
-	cmd := exec.Command(editor, filePath)
+	// Create an exec command to open the file in the editor, the editor may come with arguments like code --wait
+	command := strings.Fields(editor)
+	cmd := exec.Command(command[0], append(command[1:], filePath)...)
 
 	// Set the command to use the same standard input, output, and error streams as the Go process
 	cmd.Stdin = os.Stdin
@@ -230,14 +347,28 @@ func (fs *FileSystem) DeleteFile(fileName string, fileType string) error {
 	}
 
 	log.Println("DeleteFile called 2")
-	updateErr := fs.fileTypeIndex.DataStructure.Update(&removeFileIndexAlpha)
+	updateErr := fs.apply(fs.fileTypeIndex, &removeFileIndexAlpha)
+	if updateErr != nil {
+		return updateErr
+	}
+
+	removeFileMetaAlpha := pmfile.RemoveFileMetaAlpha{
+		FileName: fileName,
+	}
+
+	updateErr = fs.apply(fs.fileMetaIndex, &removeFileMetaAlpha)
 	if updateErr != nil {
 		return updateErr
 	}
 
 	log.Println("DeleteFile called 3")
 	// Already handles non-existent blobs
-	deleteErr := blob.DeleteBlob(fileName)
+	backupErr := fs.backupBlob(fileName)
+	if backupErr != nil {
+		return backupErr
+	}
+
+	deleteErr := blob.DeleteBlob(fs.blobDirectory(), fileName)
 	if deleteErr != nil {
 		return deleteErr
 	}
@@ -248,7 +379,7 @@ func (fs *FileSystem) DeleteFile(fileName string, fileType string) error {
 	if vertex == nil {
 		return errors.New("File not found in file system")
 	}
-	
+
 	log.Println("DeleteFile called 7")
 	parentFileTree := fs.getParentFileTree()
 	parentVertex := parentFileTree.RetrieveVertex(fileName)
@@ -267,15 +398,15 @@ func (fs *FileSystem) DeleteFile(fileName string, fileType string) error {
 			Label: directedEdge.Label,
 		}
 
-		updateErr = fs.fileRelationShips.DataStructure.Update(&removeParentChildEdgeAlpha)
+		updateErr = fs.apply(fs.fileRelationShips, &removeParentChildEdgeAlpha)
 		if updateErr != nil {
 			log.Println("Error removing vertexes pointing to this vertex " + updateErr.Error())
 			return updateErr
 		}
 
-		removeParentChildEdgeAlphaOnParentTree := dag.RemoveEdgeAlpha {
-			From: parentVertex,
-			To: directedEdge.To,
+		removeParentChildEdgeAlphaOnParentTree := dag.RemoveEdgeAlpha{
+			From:  parentVertex,
+			To:    directedEdge.To,
 			Label: directedEdge.Label,
 		}
 
@@ -306,11 +437,114 @@ func (fs *FileSystem) DeleteFile(fileName string, fileType string) error {
 	}
 
 	log.Println("DeleteFile called 6")
-	updateErr = fs.fileRelationShips.DataStructure.Update(&removeVertexAlpha)
+	updateErr = fs.apply(fs.fileRelationShips, &removeVertexAlpha)
 	if updateErr != nil {
 		return updateErr
 	}
 
+	return nil
+}
+
+// Deletes an issue while taking care of its hierarchy children.
+// refuse: fails if the issue still has children
+// cascade: deletes the issue together with its entire subtree
+// reparent: moves the children under the issue's own parent before deleting it
+// Nothing is deleted if any step fails.
+func (fs *FileSystem) DeleteIssue(fileName string, mode string) error {
+	return fs.Batch(func() error {
+		return fs.deleteIssue(fileName, mode)
+	})
+}
+
+func (fs *FileSystem) deleteIssue(fileName string, mode string) error {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return existsErr
+	}
+
+	children, childrenErr := fs.ListRelatedHierarchy(fileName)
+	if childrenErr != nil {
+		return childrenErr
+	}
+
+	switch mode {
+	case DELETE_MODE_REFUSE:
+		if len(children) > 0 {
+			return ConflictError("Issue has " + strconv.Itoa(len(children)) + " child issues, use cascade or reparent to delete it: File: " + fileName)
+		}
+	case DELETE_MODE_CASCADE:
+		for _, child := range children {
+			deleteErr := fs.deleteIssue(child, DELETE_MODE_CASCADE)
+			if deleteErr != nil {
+				return deleteErr
+			}
+		}
+	case DELETE_MODE_REPARENT:
+		parents, parentsErr := fs.ListRelatedParents(fileName, FILE_RELATIONSHIPS_HIERARCHY)
+		if parentsErr != nil {
+			return parentsErr
+		}
+
+		for _, child := range children {
+			unlinkErr := fs.UnLinkHierarchy(fileName, child)
+			if unlinkErr != nil {
+				return unlinkErr
+			}
+
+			for _, parent := range parents {
+				// The child can already sit directly under the parent as well
+				siblings, siblingsErr := fs.ListRelatedHierarchy(parent)
+				if siblingsErr != nil {
+					return siblingsErr
+				}
+
+				if slices.Contains(siblings, child) {
+					continue
+				}
+
+				linkErr := fs.LinkHierarchy(parent, child)
+				if linkErr != nil {
+					return linkErr
+				}
+			}
+		}
+	default:
+		return InvalidError("Unknown delete mode: " + mode)
+	}
+
+	detachErr := fs.detachChildren(fileName)
+	if detachErr != nil {
+		return detachErr
+	}
+
+	fileType, typeErr := fs.GetFileType(fileName)
+	if typeErr != nil {
+		return typeErr
+	}
+
+	return fs.DeleteFile(fileName, fileType)
+}
+
+// Removes every outgoing edge of a file through unLinkFile so that the
+// opposite edges on the parent tree are removed as well.
+// DeleteFile only cleans up the edges pointing to the file.
+func (fs *FileSystem) detachChildren(fileName string) error {
+	vertex := fs.getFileTree().RetrieveVertex(fileName)
+	if vertex == nil {
+		return errors.New("File not found in file system")
+	}
+
+	edges := make([]dag.DirectedEdge, 0, len(vertex.Children))
+	for _, edge := range vertex.Children {
+		edges = append(edges, *edge)
+	}
+
+	for _, edge := range edges {
+		unlinkErr := fs.unLinkFile(fileName, edge.To.ID, edge.Label)
+		if unlinkErr != nil {
+			return unlinkErr
+		}
+	}
 
 	return nil
 }
@@ -321,14 +555,14 @@ func (fs *FileSystem) validateFileExists(fileName string) error {
 	_, fileErr := fileIndex.RetrieveFileType(fileName)
 	if fileErr != nil {
 		log.Println("File not in index")
-		return errors.New("File not in index: File: " + fileName)
+		return NotFoundError("File not in index: File: " + fileName)
 	}
 
 	// Check if Parent and Child blobs exist
-	fileBlob := blob.Exists(fileName)
+	fileBlob := blob.Exists(fs.blobDirectory(), fileName)
 	if !fileBlob {
 		log.Println("File not in blobs")
-		return errors.New("File blob not found: File: " + fileName)
+		return NotFoundError("File blob not found: File: " + fileName)
 	}
 
 	// Check if Parent and Child vertex exist in the FileTree
@@ -336,14 +570,35 @@ func (fs *FileSystem) validateFileExists(fileName string) error {
 	fileVertex := fileTree.RetrieveVertex(fileName)
 	if fileVertex == nil {
 		log.Println("File not in tree")
-		return errors.New("File Vertex not found: File " + fileName)
+		return NotFoundError("File Vertex not found: File " + fileName)
 	}
 
 	return nil
 }
 
 func (fs *FileSystem) RetrieveFileContents(fileName string) (string, error) {
-	return blob.ReturnBlobContent(fileName)
+	return blob.ReturnBlobContent(fs.blobDirectory(), fileName)
+}
+
+// Replaces the markdown body of an issue
+func (fs *FileSystem) WriteFileContents(fileName string, content string) error {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return existsErr
+	}
+
+	backupErr := fs.backupBlob(fileName)
+	if backupErr != nil {
+		return backupErr
+	}
+
+	writeErr := blob.WriteBlobContent(fs.blobDirectory(), fileName, content)
+	if writeErr != nil {
+		return writeErr
+	}
+
+	fs.journalBody(fileName)
+	return nil
 }
 
 func (fs *FileSystem) LinkHierarchy(parentName string, childName string) error {
@@ -372,6 +627,12 @@ func (fs *FileSystem) linkFile(parentName string, childName string, relationship
 		return childErr
 	}
 
+	contradictionErr := fs.validateLink(parentName, childName, relationship)
+	if contradictionErr != nil {
+		log.Println(contradictionErr.Error())
+		return contradictionErr
+	}
+
 	fileTree := fs.getFileTree()
 	parentVertex := fileTree.RetrieveVertex(parentName)
 	childVertex := fileTree.RetrieveVertex(childName)
@@ -383,7 +644,7 @@ func (fs *FileSystem) linkFile(parentName string, childName string, relationship
 		Label: relationship,
 	}
 
-	updateErr := fs.fileRelationShips.DataStructure.Update(&addEdgeAlpha)
+	updateErr := fs.apply(fs.fileRelationShips, &addEdgeAlpha)
 	if updateErr != nil {
 		log.Println("Error Linking file")
 		return updateErr
@@ -444,7 +705,7 @@ func (fs *FileSystem) unLinkFile(parentName string, childName string, relationsh
 		Label: relationship,
 	}
 
-	updateErr := fs.fileRelationShips.DataStructure.Update(&removeEdgeAlpha)
+	updateErr := fs.apply(fs.fileRelationShips, &removeEdgeAlpha)
 	if updateErr != nil {
 		log.Println("Error unlinking file" + updateErr.Error())
 		return updateErr
@@ -470,6 +731,121 @@ func (fs *FileSystem) unLinkFile(parentName string, childName string, relationsh
 	return nil
 }
 
+// Returns the file types that are allowed to be the hierarchy parent of a file type.
+// A parent has to sit higher up in the hierarchy than its child.
+func ValidParentTypes(fileType string) []string {
+	var parentTypes []string
+	for _, hierarchyType := range pmfile.FILE_TYPE_HIERARCHY {
+		if hierarchyType == fileType {
+			return parentTypes
+		}
+
+		parentTypes = append(parentTypes, hierarchyType)
+	}
+
+	return nil
+}
+
+// Checks if descendant can be reached from ancestor through hierarchy edges
+func (fs *FileSystem) IsHierarchyDescendant(ancestor string, descendant string) bool {
+	children, childrenErr := fs.ListRelatedHierarchy(ancestor)
+	if childrenErr != nil {
+		return false
+	}
+
+	for _, child := range children {
+		if child == descendant || fs.IsHierarchyDescendant(child, descendant) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (fs *FileSystem) validateMove(childName string, parentName string) error {
+	childErr := fs.validateFileExists(childName)
+	if childErr != nil {
+		return childErr
+	}
+
+	parentErr := fs.validateFileExists(parentName)
+	if parentErr != nil {
+		return parentErr
+	}
+
+	if childName == parentName {
+		return InvalidError("Cannot move an issue under itself: File: " + childName)
+	}
+
+	hierarchyErr := fs.validateHierarchyLink(parentName, childName)
+	if hierarchyErr != nil {
+		return hierarchyErr
+	}
+
+	if fs.IsHierarchyDescendant(childName, parentName) {
+		return ConflictError("Cannot move an issue under one of its own child issues: File: " + parentName)
+	}
+
+	return nil
+}
+
+// Moves an issue under a new hierarchy parent.
+// The move is validated up front and the old parents are restored if linking fails
+func (fs *FileSystem) MoveIssue(childName string, parentName string) error {
+	validateErr := fs.validateMove(childName, parentName)
+	if validateErr != nil {
+		return validateErr
+	}
+
+	oldParents, parentsErr := fs.ListRelatedParents(childName, FILE_RELATIONSHIPS_HIERARCHY)
+	if parentsErr != nil {
+		return parentsErr
+	}
+
+	if indexOf(oldParents, parentName) != -1 {
+		return nil
+	}
+
+	var unlinked []string
+	for _, oldParent := range oldParents {
+		unlinkErr := fs.UnLinkHierarchy(oldParent, childName)
+		if unlinkErr != nil {
+			fs.relinkHierarchy(unlinked, childName)
+			return unlinkErr
+		}
+
+		unlinked = append(unlinked, oldParent)
+	}
+
+	linkErr := fs.LinkHierarchy(parentName, childName)
+	if linkErr != nil {
+		fs.relinkHierarchy(unlinked, childName)
+		return linkErr
+	}
+
+	return nil
+}
+
+// Restores hierarchy edges removed by a failed operation
+func (fs *FileSystem) relinkHierarchy(parents []string, childName string) {
+	for _, parent := range parents {
+		linkErr := fs.LinkHierarchy(parent, childName)
+		if linkErr != nil {
+			log.Println("Error restoring parent " + parent + " of " + childName + ": " + linkErr.Error())
+		}
+	}
+}
+
+func indexOf(haystack []string, needle string) int {
+	for index, value := range haystack {
+		if needle == value {
+			return index
+		}
+	}
+
+	return -1
+}
+
 func (fs *FileSystem) ListAllFilesWithTypes() (map[string][]string, error) {
 	fileIndex := fs.getFileIndex()
 	files, fileErr := fileIndex.RetrieveAllFilesWithTypes()
@@ -546,80 +922,102 @@ func (fs *FileSystem) GetFileType(fileName string) (string, error) {
 	fileIndex := fs.getFileIndex()
 	fileType, typeErr := fileIndex.RetrieveFileType(fileName)
 	if typeErr != nil {
-		return "", typeErr
+		return "", NotFoundError(typeErr.Error())
 	}
 
 	return fileType, nil
 }
 
-func (fs *FileSystem) GetFileChildMeta(fileName string) *dag.Vertex {
-	return fs.getFileTree().RetrieveVertex(fileName)
-}
+func (fs *FileSystem) SetFileMeta(fileName string, key string, value string) error {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return existsErr
+	}
 
-type FileGraphRenderer struct {
-	Fs FileSystem
+	setFileMetaAlpha := pmfile.SetFileMetaAlpha{
+		FileName: fileName,
+		Key:      key,
+		Value:    value,
+	}
+
+	return fs.apply(fs.fileMetaIndex, &setFileMetaAlpha)
 }
 
-func (fgr FileGraphRenderer) Build(fileName string) (string, error) {
-	output := fgr.BuildIssue(fileName, 0)
+func (fs *FileSystem) GetFileMeta(fileName string, key string) (string, bool) {
+	return fs.getFileMetaIndex().RetrieveFileMeta(fileName, key)
+}
 
-	return output, nil
+func (fs *FileSystem) GetAllFileMeta(fileName string) map[string]string {
+	return fs.getFileMetaIndex().RetrieveAllFileMeta(fileName)
 }
 
-func (fgr FileGraphRenderer) BuildIssue(vertexID string, lane int) string {
-	childrenDag := fgr.Fs.getFileTree()
-	issueWithChildren := childrenDag.RetrieveVertex(vertexID)
+// Every change of status is recorded as a transition, setting the current status is a no-op
+func (fs *FileSystem) SetFileStatus(fileName string, status string) error {
+	if indexOf(pmfile.FILE_STATUSES, status) == -1 {
+		return InvalidError("Unknown status: " + status)
+	}
 
-	head := fgr.AddHeadInLane(lane) + " " + issueWithChildren.ID
-	body := fgr.AddBodyInLane(lane)
-	dependencies := fgr.BuildDepedencies(issueWithChildren, lane)
+	previous := fs.GetFileStatus(fileName)
+	setErr := fs.SetFileMeta(fileName, pmfile.FILE_META_STATUS, status)
+	if setErr != nil || previous == status {
+		return setErr
+	}
 
-	return head + body + dependencies
+	return fs.RecordStatusTransition(fileName, pmfile.StatusTransition{
+		From: previous,
+		To:   status,
+		At:   time.Now().UTC().Format(time.RFC3339),
+	})
 }
 
-// func (fgr FileGraphRenderer) BuildHierarchy(issueWithChildren *dag.Vertex, lane int) (string) {
-// 	if len(issueWithChildren.Children) == 0 {
-// 		return ""
-// 	}
-//
-// 	var children string;
-//
-// 	for _, value := range issueWithChildren.Children {
-// 		if value.Label == FILE_RELATIONSHIPS_HIERARCHY {
-// 			children += fgr.BuildIssue(value.To.ID, lane)
-// 		}
-// 	}
-//
-// 	return children
-// }
+// Adds a transition to the history of an issue without changing its status,
+// used to bring over history recorded elsewhere
+func (fs *FileSystem) RecordStatusTransition(fileName string, transition pmfile.StatusTransition) error {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return existsErr
+	}
 
-func (fgr FileGraphRenderer) BuildDepedencies(issueWithChildren *dag.Vertex, lane int) string {
-	if len(issueWithChildren.Children) == 0 {
-		return ""
+	addTransitionAlpha := pmfile.AddTransitionAlpha{
+		FileName:   fileName,
+		Transition: transition,
 	}
 
-	var children string
+	return fs.apply(fs.fileMetaIndex, &addTransitionAlpha)
+}
 
-	for index, value := range issueWithChildren.Children {
-		if value.Label == FILE_RELATIONSHIP_DEPENDENCY {
-			position := fgr.LanePosition(lane + index)
-			children += position + "\\" + fgr.BuildIssue(value.To.ID, lane+index+1)
-		}
+func (fs *FileSystem) GetStatusTransitions(fileName string) []pmfile.StatusTransition {
+	return fs.getFileMetaIndex().RetrieveTransitions(fileName)
+}
+
+// Issues without a recorded status are still to be done
+func (fs *FileSystem) GetFileStatus(fileName string) string {
+	status, ok := fs.GetFileMeta(fileName, pmfile.FILE_META_STATUS)
+	if !ok {
+		return pmfile.FILE_STATUS_TODO
 	}
 
-	return "\n" + children
+	return status
 }
 
-func (fgr FileGraphRenderer) AddBodyInLane(lane int) string {
-	laneString := fgr.LanePosition(lane)
-	return "\n" + laneString + "|"
+func (fs *FileSystem) SetFilePriority(fileName string, priority string) error {
+	if indexOf(pmfile.FILE_PRIORITIES, priority) == -1 {
+		return InvalidError("Unknown priority: " + priority)
+	}
+
+	return fs.SetFileMeta(fileName, pmfile.FILE_META_PRIORITY, priority)
 }
 
-func (fgr FileGraphRenderer) AddHeadInLane(lane int) string {
-	laneString := fgr.LanePosition(lane)
-	return "\n" + laneString + "*"
+// Issues without a recorded priority have a medium priority
+func (fs *FileSystem) GetFilePriority(fileName string) string {
+	priority, ok := fs.GetFileMeta(fileName, pmfile.FILE_META_PRIORITY)
+	if !ok {
+		return pmfile.FILE_PRIORITY_MEDIUM
+	}
+
+	return priority
 }
 
-func (fgr FileGraphRenderer) LanePosition(lane int) string {
-	return strings.Repeat(" ", lane)
+func (fs *FileSystem) GetFileChildMeta(fileName string) *dag.Vertex {
+	return fs.getFileTree().RetrieveVertex(fileName)
 }
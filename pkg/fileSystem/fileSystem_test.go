@@ -0,0 +1,80 @@
+package fileSystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOnlyChangedStoresAreSaved(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Login"))
+	mustSucceed(t, fs.ShutDown())
+
+	stores := []string{"dag/children", "dag/parent", "fileTypes/types", "fileMeta/meta"}
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, store := range stores {
+		mustSucceed(t, os.Chtimes(filepath.Join(PROJECT_DIRECTORY, store), past, past))
+	}
+
+	modified := func() map[string]bool {
+		changed := map[string]bool{}
+		for _, store := range stores {
+			info, statErr := os.Stat(filepath.Join(PROJECT_DIRECTORY, store))
+			mustSucceed(t, statErr)
+			changed[store] = !info.ModTime().Equal(past)
+		}
+
+		return changed
+	}
+
+	reader := NewFileSystem(PROJECT_DIRECTORY)
+	mustSucceed(t, reader.Boot())
+	_, readErr := reader.ListReadyIssues()
+	mustSucceed(t, readErr)
+	mustSucceed(t, reader.ShutDown())
+
+	for store, changed := range modified() {
+		if changed {
+			t.Errorf("reading the project rewrote %s", store)
+		}
+	}
+
+	writer := NewFileSystem(PROJECT_DIRECTORY)
+	mustSucceed(t, writer.Boot())
+	mustSucceed(t, writer.SetFilePriority("Login", "high"))
+	mustSucceed(t, writer.ShutDown())
+
+	want := map[string]bool{"dag/children": false, "dag/parent": false, "fileTypes/types": false, "fileMeta/meta": true}
+	for store, changed := range modified() {
+		if changed != want[store] {
+			t.Errorf("%s rewritten: %v, want %v", store, changed, want[store])
+		}
+	}
+}
+
+func TestShutDownReportsFailedSaves(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+
+	// A directory in place of the store can't be written over
+	types := filepath.Join(PROJECT_DIRECTORY, "fileTypes", "types")
+	mustSucceed(t, os.Remove(types))
+	mustSucceed(t, os.Mkdir(types, os.ModePerm))
+	if shutDownErr := fs.ShutDown(); shutDownErr == nil {
+		t.Fatal("expected the failed save to be returned")
+	}
+
+	// The store stays dirty and is saved once it can be written again
+	mustSucceed(t, os.Remove(types))
+	mustSucceed(t, fs.ShutDown())
+
+	reader := NewFileSystem(PROJECT_DIRECTORY)
+	mustSucceed(t, reader.Boot())
+	if _, typeErr := reader.GetFileType("Login"); typeErr != nil {
+		t.Errorf("Login was not saved: %v", typeErr)
+	}
+}
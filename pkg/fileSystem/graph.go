@@ -0,0 +1,220 @@
+package fileSystem
+
+import (
+	pmfile "github/pm/pkg/file"
+
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const GRAPH_FORMAT_DOT = "dot"
+const GRAPH_FORMAT_MERMAID = "mermaid"
+
+var GRAPH_FORMATS = []string{GRAPH_FORMAT_DOT, GRAPH_FORMAT_MERMAID}
+var FILE_RELATIONSHIPS = []string{FILE_RELATIONSHIPS_HIERARCHY, FILE_RELATIONSHIP_DEPENDENCY}
+
+type GraphEdge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// Issues reachable from root over the given labels and the edges between them.
+// An empty root selects every issue, nodes and edges are sorted so the output is stable.
+func (fs *FileSystem) Subgraph(root string, labels []string) ([]string, []GraphEdge, error) {
+	fileTree := fs.getFileTree()
+
+	var nodes []string
+	if root == "" {
+		for id := range fileTree.Vertices {
+			nodes = append(nodes, id)
+		}
+	} else {
+		existsErr := fs.validateFileExists(root)
+		if existsErr != nil {
+			return nil, nil, existsErr
+		}
+
+		visited := map[string]bool{root: true}
+		queue := []string{root}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			nodes = append(nodes, current)
+
+			for _, edge := range fileTree.RetrieveVertex(current).Children {
+				if visited[edge.To.ID] || indexOf(labels, edge.Label) == -1 || !fileTree.HasVertex(edge.To.ID) {
+					continue
+				}
+
+				visited[edge.To.ID] = true
+				queue = append(queue, edge.To.ID)
+			}
+		}
+	}
+
+	sort.Strings(nodes)
+
+	included := map[string]bool{}
+	for _, node := range nodes {
+		included[node] = true
+	}
+
+	var edges []GraphEdge
+	for _, node := range nodes {
+		for _, edge := range fileTree.RetrieveVertex(node).Children {
+			if !included[edge.To.ID] || indexOf(labels, edge.Label) == -1 {
+				continue
+			}
+
+			edges = append(edges, GraphEdge{From: node, To: edge.To.ID, Label: edge.Label})
+		}
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+
+		if edges[i].Label != edges[j].Label {
+			return edges[i].Label > edges[j].Label
+		}
+
+		return edges[i].To < edges[j].To
+	})
+
+	return nodes, edges, nil
+}
+
+// Renders the issue graph for graphviz or mermaid, see Subgraph for root and labels
+func (fs *FileSystem) ExportGraph(format string, root string, labels []string) (string, error) {
+	for _, label := range labels {
+		if indexOf(FILE_RELATIONSHIPS, label) == -1 {
+			return "", InvalidError("Unknown relationship " + label)
+		}
+	}
+
+	nodes, edges, graphErr := fs.Subgraph(root, labels)
+	if graphErr != nil {
+		return "", graphErr
+	}
+
+	switch format {
+	case GRAPH_FORMAT_DOT:
+		return fs.exportDot(nodes, edges), nil
+	case GRAPH_FORMAT_MERMAID:
+		return fs.exportMermaid(nodes, edges), nil
+	}
+
+	return "", InvalidError("Unknown graph format " + format + ", expected one of " + strings.Join(GRAPH_FORMATS, ", "))
+}
+
+var dotShapes = map[string]string{
+	pmfile.FILE_TYPE_EPIC:  "box3d",
+	pmfile.FILE_TYPE_STORY: "box",
+	pmfile.FILE_TYPE_TASK:  "ellipse",
+}
+
+var statusColors = map[string]string{
+	pmfile.FILE_STATUS_TODO:        "#ffffff",
+	pmfile.FILE_STATUS_IN_PROGRESS: "#fff3b0",
+	pmfile.FILE_STATUS_DONE:        "#c8e6c9",
+}
+
+func (fs *FileSystem) nodeDescription(fileName string) (string, string) {
+	fileType, typeErr := fs.GetFileType(fileName)
+	if typeErr != nil {
+		fileType = ""
+	}
+
+	return fileType, fs.GetFileStatus(fileName)
+}
+
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	return "\"" + strings.ReplaceAll(value, "\"", "\\\"") + "\""
+}
+
+func (fs *FileSystem) exportDot(nodes []string, edges []GraphEdge) string {
+	var builder strings.Builder
+	builder.WriteString("digraph pm {\n")
+	builder.WriteString("  rankdir=LR;\n")
+	builder.WriteString("  node [style=filled, fontname=\"Helvetica\"];\n")
+
+	for _, node := range nodes {
+		fileType, status := fs.nodeDescription(node)
+		shape, ok := dotShapes[fileType]
+		if !ok {
+			shape = "box"
+		}
+
+		fmt.Fprintf(&builder, "  %s [label=%s, shape=%s, fillcolor=%s];\n",
+			dotQuote(node), dotQuote(node+"\n"+fileType+" · "+status), shape, dotQuote(statusColors[status]))
+	}
+
+	for _, edge := range edges {
+		if edge.Label == FILE_RELATIONSHIP_DEPENDENCY {
+			fmt.Fprintf(&builder, "  %s -> %s [style=dashed, label=\"blocks\"];\n", dotQuote(edge.From), dotQuote(edge.To))
+			continue
+		}
+
+		fmt.Fprintf(&builder, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// Labels are html, names are escaped with mermaid's entity codes
+var mermaidEscaper = strings.NewReplacer("#", "#35;", "\"", "#quot;", "<", "#lt;", ">", "#gt;")
+
+// Mermaid ids can't hold arbitrary issue names so nodes are numbered
+func mermaidNode(id string, label string, fileType string) string {
+	label = "\"" + label + "\""
+
+	switch fileType {
+	case pmfile.FILE_TYPE_EPIC:
+		return id + "[[" + label + "]]"
+	case pmfile.FILE_TYPE_STORY:
+		return id + "(" + label + ")"
+	}
+
+	return id + "[" + label + "]"
+}
+
+func mermaidClass(status string) string {
+	return strings.ReplaceAll(status, "-", "")
+}
+
+func (fs *FileSystem) exportMermaid(nodes []string, edges []GraphEdge) string {
+	var builder strings.Builder
+	builder.WriteString("flowchart LR\n")
+
+	ids := map[string]string{}
+	for index, node := range nodes {
+		ids[node] = fmt.Sprintf("n%d", index)
+	}
+
+	for _, node := range nodes {
+		fileType, status := fs.nodeDescription(node)
+		label := mermaidEscaper.Replace(node) + "<br/>" + fileType + " · " + status
+		fmt.Fprintf(&builder, "  %s:::%s\n", mermaidNode(ids[node], label, fileType), mermaidClass(status))
+	}
+
+	for _, edge := range edges {
+		if edge.Label == FILE_RELATIONSHIP_DEPENDENCY {
+			fmt.Fprintf(&builder, "  %s -.->|blocks| %s\n", ids[edge.From], ids[edge.To])
+			continue
+		}
+
+		fmt.Fprintf(&builder, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+
+	for _, status := range pmfile.FILE_STATUSES {
+		fmt.Fprintf(&builder, "  classDef %s fill:%s,stroke:#333\n", mermaidClass(status), statusColors[status])
+	}
+
+	return builder.String()
+}
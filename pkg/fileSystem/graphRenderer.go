@@ -0,0 +1,339 @@
+package fileSystem
+
+import (
+	"sort"
+	"strings"
+)
+
+/**
+Draws the issue graph one issue per row like git log --graph.
+
+Every edge that still has to reach its issue holds a lane. When an issue is
+drawn the lanes waiting for it merge into its column, then its edges fork out
+into lanes of their own. Shared issues are drawn once and lanes are reused
+once they are free, so diamonds don't duplicate issues or collide.
+
+HIERARCHY edges are drawn solid (│) and DEPENDENCY edges dashed (┆).
+*/
+
+type FileGraphRenderer struct {
+	Fs FileSystem
+	// Issues drawn with a distinct marker, such as the issues on the critical path
+	Highlighted map[string]bool
+	// Relationships to draw, every relationship when empty
+	Labels []string
+	// Issues whose outgoing edges are hidden
+	Collapsed map[string]bool
+}
+
+// A row of the graph, FileName is empty for rows that only connect lanes.
+// Collapsed is set when the issue has edges hidden by FileGraphRenderer.Collapsed.
+type GraphRow struct {
+	Lanes     string
+	FileName  string
+	Collapsed bool
+}
+
+type graphLane struct {
+	target string
+	label  string
+}
+
+func (fgr FileGraphRenderer) Build(fileName string) (string, error) {
+	rows, rowsErr := fgr.Rows(fileName)
+	if rowsErr != nil {
+		return "", rowsErr
+	}
+
+	lines := make([]string, len(rows))
+	for index, row := range rows {
+		line := row.Lanes + " " + row.FileName
+		if row.Collapsed {
+			line += " [+]"
+		}
+
+		lines[index] = strings.TrimRight(line, " ")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// Lays out the issues reachable from fileName, or every issue when fileName is empty
+func (fgr FileGraphRenderer) Rows(fileName string) ([]GraphRow, error) {
+	labels := fgr.Labels
+	if len(labels) == 0 {
+		labels = FILE_RELATIONSHIPS
+	}
+
+	nodes, edges, graphErr := fgr.Fs.Subgraph(fileName, labels)
+	if graphErr != nil {
+		return nil, graphErr
+	}
+
+	nodes, edges, hidden := fgr.collapse(nodes, edges)
+
+	children := map[string][]GraphEdge{}
+	for _, edge := range edges {
+		children[edge.From] = append(children[edge.From], edge)
+	}
+
+	var rows []GraphRow
+	var lanes []graphLane
+	for _, node := range graphOrder(nodes, edges) {
+		var waiting []int
+		for index, lane := range lanes {
+			if lane.target == node {
+				waiting = append(waiting, index)
+			}
+		}
+
+		if len(waiting) == 0 {
+			waiting = []int{allocateLane(&lanes, 0)}
+		}
+
+		column := waiting[0]
+		if len(waiting) > 1 {
+			rows = append(rows, GraphRow{Lanes: joinRow(lanes, column, waiting[1:], "╯", "┴")})
+			for _, index := range waiting[1:] {
+				lanes[index] = graphLane{}
+			}
+
+			lanes = trimLanes(lanes)
+		}
+
+		rows = append(rows, GraphRow{Lanes: fgr.nodeRow(lanes, column, node), FileName: node, Collapsed: hidden[node]})
+
+		outgoing := children[node]
+		if len(outgoing) == 0 {
+			lanes[column] = graphLane{}
+			lanes = trimLanes(lanes)
+			continue
+		}
+
+		lanes[column] = graphLane{target: outgoing[0].To, label: outgoing[0].Label}
+
+		var forks []int
+		for _, edge := range outgoing[1:] {
+			index := allocateLane(&lanes, column+1)
+			lanes[index] = graphLane{target: edge.To, label: edge.Label}
+			forks = append(forks, index)
+		}
+
+		if len(forks) > 0 {
+			rows = append(rows, GraphRow{Lanes: joinRow(lanes, column, forks, "╮", "┬")})
+		}
+
+		lanes = trimLanes(lanes)
+	}
+
+	return rows, nil
+}
+
+// Drops the edges leaving collapsed issues and the issues only reachable through them.
+// Returns the remaining nodes and edges and the collapsed issues that hide edges.
+func (fgr FileGraphRenderer) collapse(nodes []string, edges []GraphEdge) ([]string, []GraphEdge, map[string]bool) {
+	hidden := map[string]bool{}
+	if len(fgr.Collapsed) == 0 {
+		return nodes, edges, hidden
+	}
+
+	children := map[string][]string{}
+	incoming := map[string]int{}
+	for _, edge := range edges {
+		children[edge.From] = append(children[edge.From], edge.To)
+		incoming[edge.To]++
+	}
+
+	visible := map[string]bool{}
+	var queue []string
+	for _, node := range nodes {
+		if incoming[node] == 0 {
+			visible[node] = true
+			queue = append(queue, node)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if fgr.Collapsed[current] {
+			hidden[current] = len(children[current]) > 0
+			continue
+		}
+
+		for _, child := range children[current] {
+			if !visible[child] {
+				visible[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	var visibleNodes []string
+	for _, node := range nodes {
+		if visible[node] {
+			visibleNodes = append(visibleNodes, node)
+		}
+	}
+
+	var visibleEdges []GraphEdge
+	for _, edge := range edges {
+		if visible[edge.From] && !fgr.Collapsed[edge.From] && visible[edge.To] {
+			visibleEdges = append(visibleEdges, edge)
+		}
+	}
+
+	return visibleNodes, visibleEdges, hidden
+}
+
+// Orders the issues so that every issue comes after the issues pointing to it,
+// with the first child of an issue drawn right below it where possible
+func graphOrder(nodes []string, edges []GraphEdge) []string {
+	children := map[string][]string{}
+	incoming := map[string]int{}
+	for _, edge := range edges {
+		children[edge.From] = append(children[edge.From], edge.To)
+		incoming[edge.To]++
+	}
+
+	var roots []string
+	for _, node := range nodes {
+		if incoming[node] == 0 {
+			roots = append(roots, node)
+		}
+	}
+
+	visited := map[string]bool{}
+	var postOrder []string
+	var visit func(node string)
+	visit = func(node string) {
+		visited[node] = true
+		next := children[node]
+		for index := len(next) - 1; index >= 0; index-- {
+			if !visited[next[index]] {
+				visit(next[index])
+			}
+		}
+
+		postOrder = append(postOrder, node)
+	}
+
+	// Post order lists the last visited root first once reversed
+	var order []string
+	visitAll := func(starts []string) {
+		postOrder = nil
+		for index := len(starts) - 1; index >= 0; index-- {
+			if !visited[starts[index]] {
+				visit(starts[index])
+			}
+		}
+
+		for index := len(postOrder) - 1; index >= 0; index-- {
+			order = append(order, postOrder[index])
+		}
+	}
+
+	visitAll(roots)
+	// Issues caught in a cycle can't be reached from a root, they are still drawn
+	visitAll(nodes)
+
+	return order
+}
+
+// Returns the first free lane from start onwards, adding a lane if none is free
+func allocateLane(lanes *[]graphLane, start int) int {
+	for index := start; index < len(*lanes); index++ {
+		if (*lanes)[index].target == "" {
+			return index
+		}
+	}
+
+	for len(*lanes) < start {
+		*lanes = append(*lanes, graphLane{})
+	}
+
+	*lanes = append(*lanes, graphLane{})
+	return len(*lanes) - 1
+}
+
+func trimLanes(lanes []graphLane) []graphLane {
+	for len(lanes) > 0 && lanes[len(lanes)-1].target == "" {
+		lanes = lanes[:len(lanes)-1]
+	}
+
+	return lanes
+}
+
+func (lane graphLane) line() string {
+	if lane.target == "" {
+		return " "
+	}
+
+	if lane.label == FILE_RELATIONSHIP_DEPENDENCY {
+		return "┆"
+	}
+
+	return "│"
+}
+
+func (fgr FileGraphRenderer) nodeRow(lanes []graphLane, column int, fileName string) string {
+	var row strings.Builder
+	for index, lane := range lanes {
+		if index == column {
+			if fgr.Highlighted[fileName] {
+				row.WriteString("◆")
+			} else {
+				row.WriteString("●")
+			}
+		} else {
+			row.WriteString(lane.line())
+		}
+
+		if index < len(lanes)-1 {
+			row.WriteString(" ")
+		}
+	}
+
+	return row.String()
+}
+
+// Connects column to the joined lanes on its right, either lanes merging into
+// the column or lanes forking out of it. The furthest lane gets the corner.
+func joinRow(lanes []graphLane, column int, joined []int, corner string, tee string) string {
+	sort.Ints(joined)
+	last := joined[len(joined)-1]
+
+	isJoined := map[int]bool{}
+	for _, index := range joined {
+		isJoined[index] = true
+	}
+
+	var row strings.Builder
+	for index, lane := range lanes {
+		switch {
+		case index == column:
+			row.WriteString("├")
+		case index == last:
+			row.WriteString(corner)
+		case isJoined[index]:
+			row.WriteString(tee)
+		case index > column && index < last && lane.target != "":
+			row.WriteString("┼")
+		case index > column && index < last:
+			row.WriteString("─")
+		default:
+			row.WriteString(lane.line())
+		}
+
+		if index < len(lanes)-1 {
+			if index >= column && index < last {
+				row.WriteString("─")
+			} else {
+				row.WriteString(" ")
+			}
+		}
+	}
+
+	return row.String()
+}
@@ -0,0 +1,56 @@
+package fileSystem
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiamondLayout(t *testing.T) {
+	fs := bootDiamond(t)
+	mustSucceed(t, fs.CreateFile("Backend", "epic"))
+	mustSucceed(t, fs.LinkHierarchy("Backend", "Schema"))
+
+	// Deploy is shared by both branches, it is drawn once where the lanes merge
+	graph, buildErr := FileGraphRenderer{Fs: *fs}.Build("")
+	mustSucceed(t, buildErr)
+
+	want := strings.Join([]string{
+		"● Backend",
+		"● Schema",
+		"├─╮",
+		"● ┆ Api",
+		"┆ ● Ui",
+		"├─╯",
+		"● Deploy",
+	}, "\n")
+
+	if graph != want {
+		t.Errorf("graph is\n%s\nwant\n%s", graph, want)
+	}
+}
+
+func TestCollapsedAndHighlightedLayout(t *testing.T) {
+	fs := bootDiamond(t)
+
+	renderer := FileGraphRenderer{
+		Fs:          *fs,
+		Highlighted: map[string]bool{"Ui": true},
+		Collapsed:   map[string]bool{"Api": true},
+	}
+
+	graph, buildErr := renderer.Build("Schema")
+	mustSucceed(t, buildErr)
+
+	// Deploy stays reachable through Ui, the lane freed by Api is not redrawn
+	want := strings.Join([]string{
+		"● Schema",
+		"├─╮",
+		"● ┆ Api [+]",
+		"  ◆ Ui",
+		"  ● Deploy",
+	}, "\n")
+
+	if graph != want {
+		t.Errorf("graph is\n%s\nwant\n%s", graph, want)
+	}
+}
@@ -0,0 +1,131 @@
+package fileSystem
+
+import (
+	"errors"
+	"testing"
+)
+
+// Launch contains Auth, which contains two tasks with names that need quoting
+func bootGraph(t *testing.T) *FileSystem {
+	t.Helper()
+
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Launch", "epic"))
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile(`Say "hi"`, "task"))
+	mustSucceed(t, fs.CreateFile("C# <Port>", "task"))
+	mustSucceed(t, fs.CreateFile("Billing", "epic"))
+	mustSucceed(t, fs.LinkHierarchy("Launch", "Auth"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", `Say "hi"`))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "C# <Port>"))
+	mustSucceed(t, fs.LinkDependency(`Say "hi"`, "C# <Port>"))
+	mustSucceed(t, fs.LinkDependency("Launch", "Billing"))
+	mustSucceed(t, fs.SetFileStatus(`Say "hi"`, "in-progress"))
+
+	return fs
+}
+
+func TestExportGraph(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		root   string
+		labels []string
+		output string
+	}{
+		{
+			name:   "dot of everything",
+			format: GRAPH_FORMAT_DOT,
+			labels: FILE_RELATIONSHIPS,
+			output: `digraph pm {
+  rankdir=LR;
+  node [style=filled, fontname="Helvetica"];
+  "Auth" [label="Auth\nstory · todo", shape=box, fillcolor="#ffffff"];
+  "Billing" [label="Billing\nepic · todo", shape=box3d, fillcolor="#ffffff"];
+  "C# <Port>" [label="C# <Port>\ntask · todo", shape=ellipse, fillcolor="#ffffff"];
+  "Launch" [label="Launch\nepic · todo", shape=box3d, fillcolor="#ffffff"];
+  "Say \"hi\"" [label="Say \"hi\"\ntask · in-progress", shape=ellipse, fillcolor="#fff3b0"];
+  "Auth" -> "C# <Port>";
+  "Auth" -> "Say \"hi\"";
+  "Launch" -> "Auth";
+  "Launch" -> "Billing" [style=dashed, label="blocks"];
+  "Say \"hi\"" -> "C# <Port>" [style=dashed, label="blocks"];
+}
+`,
+		},
+		{
+			name:   "dot of the hierarchy below Auth",
+			format: GRAPH_FORMAT_DOT,
+			root:   "Auth",
+			labels: []string{FILE_RELATIONSHIPS_HIERARCHY},
+			output: `digraph pm {
+  rankdir=LR;
+  node [style=filled, fontname="Helvetica"];
+  "Auth" [label="Auth\nstory · todo", shape=box, fillcolor="#ffffff"];
+  "C# <Port>" [label="C# <Port>\ntask · todo", shape=ellipse, fillcolor="#ffffff"];
+  "Say \"hi\"" [label="Say \"hi\"\ntask · in-progress", shape=ellipse, fillcolor="#fff3b0"];
+  "Auth" -> "C# <Port>";
+  "Auth" -> "Say \"hi\"";
+}
+`,
+		},
+		{
+			name:   "mermaid of the dependencies from Launch",
+			format: GRAPH_FORMAT_MERMAID,
+			root:   "Launch",
+			labels: []string{FILE_RELATIONSHIP_DEPENDENCY},
+			output: `flowchart LR
+  n0[["Billing<br/>epic · todo"]]:::todo
+  n1[["Launch<br/>epic · todo"]]:::todo
+  n1 -.->|blocks| n0
+  classDef todo fill:#ffffff,stroke:#333
+  classDef inprogress fill:#fff3b0,stroke:#333
+  classDef done fill:#c8e6c9,stroke:#333
+`,
+		},
+		{
+			name:   "mermaid of the tree below Auth",
+			format: GRAPH_FORMAT_MERMAID,
+			root:   "Auth",
+			labels: FILE_RELATIONSHIPS,
+			output: `flowchart LR
+  n0("Auth<br/>story · todo"):::todo
+  n1["C#35; #lt;Port#gt;<br/>task · todo"]:::todo
+  n2["Say #quot;hi#quot;<br/>task · in-progress"]:::inprogress
+  n0 --> n1
+  n0 --> n2
+  n2 -.->|blocks| n1
+  classDef todo fill:#ffffff,stroke:#333
+  classDef inprogress fill:#fff3b0,stroke:#333
+  classDef done fill:#c8e6c9,stroke:#333
+`,
+		},
+	}
+
+	fs := bootGraph(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, exportErr := fs.ExportGraph(test.format, test.root, test.labels)
+			mustSucceed(t, exportErr)
+			if output != test.output {
+				t.Errorf("graph is\n%s\nwant\n%s", output, test.output)
+			}
+		})
+	}
+}
+
+func TestExportGraphRejectsInvalidInput(t *testing.T) {
+	fs := bootGraph(t)
+
+	if _, formatErr := fs.ExportGraph("svg", "", FILE_RELATIONSHIPS); !errors.Is(formatErr, ErrInvalid) {
+		t.Errorf("unknown format gave %v", formatErr)
+	}
+
+	if _, labelErr := fs.ExportGraph(GRAPH_FORMAT_DOT, "", []string{"BLOCKS"}); !errors.Is(labelErr, ErrInvalid) {
+		t.Errorf("unknown relationship gave %v", labelErr)
+	}
+
+	if _, rootErr := fs.ExportGraph(GRAPH_FORMAT_DOT, "Signup", FILE_RELATIONSHIPS); !errors.Is(rootErr, ErrNotFound) {
+		t.Errorf("missing root gave %v", rootErr)
+	}
+}
@@ -0,0 +1,191 @@
+package fileSystem
+
+import (
+	pmfile "github/pm/pkg/file"
+
+	"strings"
+)
+
+/**
+Validated issue operations for callers outside the TUI, such as the CLI.
+CreateFile and linkFile trust their input, these check names, types and
+relationships first so a bad argument can't corrupt the project.
+*/
+
+// Issue names are also blob file names
+func ValidateIssueName(fileName string) error {
+	if strings.TrimSpace(fileName) == "" {
+		return InvalidError("Issue name cannot be empty")
+	}
+
+	if fileName != strings.TrimSpace(fileName) {
+		return InvalidError("Issue name cannot start or end with spaces: " + fileName)
+	}
+
+	if strings.ContainsAny(fileName, "/\\\n\r") || fileName == "." || fileName == ".." {
+		return InvalidError("Issue name cannot contain path separators or line breaks: " + fileName)
+	}
+
+	return nil
+}
+
+// Maps the lower case names used on the command line to relationship labels
+func ParseRelationship(relationship string) (string, error) {
+	label := strings.ToUpper(strings.TrimSpace(relationship))
+	if indexOf(FILE_RELATIONSHIPS, label) == -1 {
+		return "", InvalidError("Unknown relationship " + relationship + ", expected hierarchy or dependency")
+	}
+
+	return label, nil
+}
+
+func (fs *FileSystem) validateHierarchyLink(parentName string, childName string) error {
+	childType, childTypeErr := fs.GetFileType(childName)
+	if childTypeErr != nil {
+		return childTypeErr
+	}
+
+	parentType, parentTypeErr := fs.GetFileType(parentName)
+	if parentTypeErr != nil {
+		return parentTypeErr
+	}
+
+	if indexOf(ValidParentTypes(childType), parentType) == -1 {
+		return InvalidError("A " + parentType + " cannot be the parent of a " + childType)
+	}
+
+	return nil
+}
+
+// Creates an issue with body, links it under parentName when given and makes it depend on
+// every issue in dependsOn. Nothing is created if any link fails.
+func (fs *FileSystem) CreateIssue(fileName string, fileType string, parentName string, dependsOn []string, body string) error {
+	nameErr := ValidateIssueName(fileName)
+	if nameErr != nil {
+		return nameErr
+	}
+
+	if indexOf(pmfile.FILE_TYPE_HIERARCHY, fileType) == -1 {
+		return InvalidError("Unknown type " + fileType + ", expected one of " + strings.Join(pmfile.FILE_TYPE_HIERARCHY, ", "))
+	}
+
+	// CreateFile overwrites the blob of an existing issue
+	if fs.validateFileExists(fileName) == nil {
+		return ConflictError("Issue already exists: " + fileName)
+	}
+
+	if parentName != "" {
+		parentErr := fs.validateFileExists(parentName)
+		if parentErr != nil {
+			return parentErr
+		}
+
+		parentType, parentTypeErr := fs.GetFileType(parentName)
+		if parentTypeErr != nil {
+			return parentTypeErr
+		}
+
+		if indexOf(ValidParentTypes(fileType), parentType) == -1 {
+			return InvalidError("A " + parentType + " cannot be the parent of a " + fileType)
+		}
+	}
+
+	for _, upstream := range dependsOn {
+		upstreamErr := fs.validateFileExists(upstream)
+		if upstreamErr != nil {
+			return upstreamErr
+		}
+	}
+
+	return fs.Batch(func() error {
+		createErr := fs.CreateFile(fileName, fileType)
+		if createErr != nil {
+			return createErr
+		}
+
+		if body != "" {
+			bodyErr := fs.WriteFileContents(fileName, body)
+			if bodyErr != nil {
+				return bodyErr
+			}
+		}
+
+		linkErr := fs.LinkHierarchy(parentName, fileName)
+		if linkErr != nil {
+			return linkErr
+		}
+
+		for _, upstream := range dependsOn {
+			dependencyErr := fs.Link(upstream, fileName, FILE_RELATIONSHIP_DEPENDENCY)
+			if dependencyErr != nil {
+				return dependencyErr
+			}
+		}
+
+		return nil
+	})
+}
+
+// Replaces the body of an issue, the old body is kept if writing fails
+func (fs *FileSystem) SetIssueBody(fileName string, body string) error {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return existsErr
+	}
+
+	return fs.Batch(func() error {
+		return fs.WriteFileContents(fileName, body)
+	})
+}
+
+// Links two issues, hierarchy links have to go from a higher to a lower type
+func (fs *FileSystem) Link(parentName string, childName string, relationship string) error {
+	if parentName == childName {
+		return InvalidError("Cannot link an issue to itself: " + parentName)
+	}
+
+	for _, fileName := range []string{parentName, childName} {
+		existsErr := fs.validateFileExists(fileName)
+		if existsErr != nil {
+			return existsErr
+		}
+	}
+
+	related, relatedErr := fs.ListRelatedIssues(parentName, relationship)
+	if relatedErr != nil {
+		return relatedErr
+	}
+
+	if indexOf(related, childName) != -1 {
+		return ConflictError("Issues are already linked: " + parentName + " and " + childName)
+	}
+
+	if relationship == FILE_RELATIONSHIPS_HIERARCHY {
+		hierarchyErr := fs.validateHierarchyLink(parentName, childName)
+		if hierarchyErr != nil {
+			return hierarchyErr
+		}
+	}
+
+	return fs.linkFile(parentName, childName, relationship)
+}
+
+func (fs *FileSystem) Unlink(parentName string, childName string, relationship string) error {
+	for _, fileName := range []string{parentName, childName} {
+		existsErr := fs.validateFileExists(fileName)
+		if existsErr != nil {
+			return existsErr
+		}
+	}
+
+	related, relatedErr := fs.ListRelatedIssues(parentName, relationship)
+	if relatedErr != nil {
+		return relatedErr
+	}
+
+	if indexOf(related, childName) == -1 {
+		return NotFoundError("Issues are not linked: " + parentName + " and " + childName)
+	}
+
+	return fs.unLinkFile(parentName, childName, relationship)
+}
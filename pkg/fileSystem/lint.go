@@ -0,0 +1,282 @@
+package fileSystem
+
+import (
+	"sort"
+	"strings"
+)
+
+/**
+Labels on their own can't form cycles (see dag.AddEdge), but combined they can
+describe plans that can't be executed, like a task that depends on its own epic.
+
+To find them every issue is split into a start and a finish event:
+- an issue starts before it finishes
+- HIERARCHY parent -> child: the parent starts before the child and the child
+  finishes before the parent
+- DEPENDENCY upstream -> downstream: the upstream finishes before the downstream starts
+
+A plan is contradictory when these events form a cycle.
+*/
+
+const (
+	eventStart  = "start"
+	eventFinish = "finish"
+)
+
+type planEvent struct {
+	FileName string
+	Kind     string
+}
+
+type planStep struct {
+	To     planEvent
+	Reason string // Empty for the implicit start before finish step
+}
+
+type planGraph map[planEvent][]planStep
+
+// An impossible plan, Steps explains the cycle one edge at a time
+type Contradiction struct {
+	Issues []string
+	Steps  []string
+}
+
+func (c Contradiction) String() string {
+	return strings.Join(c.Steps, " → ")
+}
+
+func (pg planGraph) addIssue(fileName string) {
+	start := planEvent{FileName: fileName, Kind: eventStart}
+	finish := planEvent{FileName: fileName, Kind: eventFinish}
+
+	if _, ok := pg[start]; ok {
+		return
+	}
+
+	pg[start] = []planStep{{To: finish}}
+	pg[finish] = []planStep{}
+}
+
+type planEdge struct {
+	From planEvent
+	Step planStep
+}
+
+func linkEdges(parentName string, childName string, relationship string) []planEdge {
+	switch relationship {
+	case FILE_RELATIONSHIPS_HIERARCHY:
+		reason := parentName + " contains " + childName
+		return []planEdge{
+			{
+				From: planEvent{FileName: parentName, Kind: eventStart},
+				Step: planStep{To: planEvent{FileName: childName, Kind: eventStart}, Reason: reason},
+			},
+			{
+				From: planEvent{FileName: childName, Kind: eventFinish},
+				Step: planStep{To: planEvent{FileName: parentName, Kind: eventFinish}, Reason: reason},
+			},
+		}
+	case FILE_RELATIONSHIP_DEPENDENCY:
+		return []planEdge{
+			{
+				From: planEvent{FileName: parentName, Kind: eventFinish},
+				Step: planStep{To: planEvent{FileName: childName, Kind: eventStart}, Reason: childName + " depends on " + parentName},
+			},
+		}
+	}
+
+	return nil
+}
+
+func (pg planGraph) addLink(parentName string, childName string, relationship string) []planEdge {
+	pg.addIssue(parentName)
+	pg.addIssue(childName)
+
+	edges := linkEdges(parentName, childName, relationship)
+	for _, edge := range edges {
+		pg[edge.From] = append(pg[edge.From], edge.Step)
+	}
+
+	return edges
+}
+
+// Breadth first search, returns the steps leading from one event to the other or nil
+func (pg planGraph) findPath(from planEvent, to planEvent) []planStep {
+	previous := map[planEvent]planEdge{}
+	visited := map[planEvent]bool{from: true}
+	queue := []planEvent{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == to {
+			path := []planStep{}
+			for current != from {
+				edge := previous[current]
+				path = append([]planStep{edge.Step}, path...)
+				current = edge.From
+			}
+
+			return path
+		}
+
+		for _, step := range pg[current] {
+			if visited[step.To] {
+				continue
+			}
+
+			visited[step.To] = true
+			previous[step.To] = planEdge{From: current, Step: step}
+			queue = append(queue, step.To)
+		}
+	}
+
+	return nil
+}
+
+func (fs *FileSystem) buildPlanGraph() planGraph {
+	graph := planGraph{}
+	fileTree := fs.getFileTree()
+
+	ids := make([]string, 0, len(fileTree.Vertices))
+	for id := range fileTree.Vertices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		graph.addIssue(id)
+		for _, edge := range fileTree.Vertices[id].Children {
+			if !fileTree.HasVertex(edge.To.ID) {
+				continue
+			}
+
+			graph.addLink(id, edge.To.ID, edge.Label)
+		}
+	}
+
+	return graph
+}
+
+// Depth first search from event, returns the steps of the first cycle found
+func (pg planGraph) findCycle(event planEvent, state map[planEvent]int, path []planStep, from []planEvent) []planStep {
+	const visiting = 1
+	const visited = 2
+
+	state[event] = visiting
+	from = append(from, event)
+
+	for _, step := range pg[event] {
+		switch state[step.To] {
+		case visiting:
+			// Keep the part of the path that starts at the repeated event
+			for index, previous := range from {
+				if previous == step.To {
+					cycle := append([]planStep{}, path[index:]...)
+					return append(cycle, step)
+				}
+			}
+		case visited:
+			continue
+		default:
+			cycle := pg.findCycle(step.To, state, append(path, step), from)
+			if cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	state[event] = visited
+	return nil
+}
+
+func toContradiction(cycle []planStep) Contradiction {
+	var steps []string
+	seen := map[string]bool{}
+	var issues []string
+
+	for _, step := range cycle {
+		if !seen[step.To.FileName] {
+			seen[step.To.FileName] = true
+			issues = append(issues, step.To.FileName)
+		}
+
+		if step.Reason != "" {
+			steps = append(steps, step.Reason)
+		}
+	}
+
+	sort.Strings(issues)
+	return Contradiction{
+		Issues: issues,
+		Steps:  steps,
+	}
+}
+
+// Checks if a new edge would make the plan contradictory before it is added.
+// Only cycles going through the new edge are reported.
+func (fs *FileSystem) validateLink(parentName string, childName string, relationship string) error {
+	graph := fs.buildPlanGraph()
+	edges := graph.addLink(parentName, childName, relationship)
+
+	for _, edge := range edges {
+		path := graph.findPath(edge.Step.To, edge.From)
+		if path == nil {
+			continue
+		}
+
+		cycle := append([]planStep{edge.Step}, path...)
+		return ConflictError("Link would make the plan contradictory: " + toContradiction(cycle).String())
+	}
+
+	return nil
+}
+
+// Scans the whole project for contradictions between hierarchy and dependency edges.
+// Every group of issues caught in a cycle is reported once.
+func (fs *FileSystem) Lint() []Contradiction {
+	graph := fs.buildPlanGraph()
+
+	events := make([]planEvent, 0, len(graph))
+	for event := range graph {
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].FileName != events[j].FileName {
+			return events[i].FileName < events[j].FileName
+		}
+
+		return events[i].Kind > events[j].Kind
+	})
+
+	var contradictions []Contradiction
+	reported := map[string]bool{}
+	reportedIssues := map[string]bool{}
+	for _, event := range events {
+		if reportedIssues[event.FileName] {
+			continue
+		}
+
+		cycle := graph.findCycle(event, map[planEvent]int{}, nil, nil)
+		if cycle == nil {
+			continue
+		}
+
+		contradiction := toContradiction(cycle)
+		key := strings.Join(contradiction.Issues, "\x00")
+		if reported[key] {
+			continue
+		}
+
+		reported[key] = true
+		for _, issue := range contradiction.Issues {
+			reportedIssues[issue] = true
+		}
+
+		contradictions = append(contradictions, contradiction)
+	}
+
+	return contradictions
+}
@@ -0,0 +1,71 @@
+package fileSystem
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github/pm/pkg/dag"
+)
+
+func TestLinkContradictingHierarchyIsRejected(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Launch", "epic"))
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+	mustSucceed(t, fs.LinkHierarchy("Launch", "Auth"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Login"))
+
+	// Launch only finishes after Login, so Login can't wait for Launch to finish
+	linkErr := fs.LinkDependency("Launch", "Login")
+	if !errors.Is(linkErr, ErrConflict) {
+		t.Fatalf("expected a conflict, got %v", linkErr)
+	}
+
+	if want := "Login depends on Launch → Auth contains Login → Launch contains Auth"; !strings.Contains(linkErr.Error(), want) {
+		t.Errorf("error %q does not explain the cycle %q", linkErr.Error(), want)
+	}
+
+	if related, _ := fs.ListRelatedDependency("Launch"); len(related) != 0 {
+		t.Errorf("rejected link was added: %v", related)
+	}
+
+	// Siblings may depend on each other
+	mustSucceed(t, fs.CreateFile("Logout", "task"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Logout"))
+	mustSucceed(t, fs.LinkDependency("Login", "Logout"))
+}
+
+func TestLintFindsContradictions(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Login", "task"))
+	mustSucceed(t, fs.CreateFile("Logout", "task"))
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Login"))
+	mustSucceed(t, fs.LinkDependency("Login", "Logout"))
+
+	if contradictions := fs.Lint(); len(contradictions) != 0 {
+		t.Fatalf("consistent plan reported as %v", contradictions)
+	}
+
+	// Projects written before links were validated can already contain contradictions
+	fileTree := fs.getFileTree()
+	mustSucceed(t, fileTree.Update(&dag.AddEdgeAlpha{
+		From:  fileTree.RetrieveVertex("Login"),
+		To:    fileTree.RetrieveVertex("Auth"),
+		Label: FILE_RELATIONSHIP_DEPENDENCY,
+	}))
+
+	contradictions := fs.Lint()
+	if len(contradictions) != 1 {
+		t.Fatalf("expected one contradiction, got %v", contradictions)
+	}
+
+	if issues := strings.Join(contradictions[0].Issues, ", "); issues != "Auth, Login" {
+		t.Errorf("contradiction involves %s", issues)
+	}
+
+	if steps := contradictions[0].String(); !strings.Contains(steps, "Auth depends on Login") || !strings.Contains(steps, "Auth contains Login") {
+		t.Errorf("contradiction is explained as %q", steps)
+	}
+}
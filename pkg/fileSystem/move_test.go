@@ -0,0 +1,76 @@
+package fileSystem
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	pmfile "github/pm/pkg/file"
+)
+
+func TestMoveIssue(t *testing.T) {
+	fs := bootHierarchy(t)
+	mustSucceed(t, fs.CreateFile("Accounts", "story"))
+	mustSucceed(t, fs.LinkHierarchy("Launch", "Accounts"))
+
+	mustSucceed(t, fs.MoveIssue("Login", "Accounts"))
+	if parents, _ := fs.ListRelatedParents("Login", FILE_RELATIONSHIPS_HIERARCHY); strings.Join(parents, ", ") != "Accounts" {
+		t.Errorf("Login is under %v, want Accounts", parents)
+	}
+
+	if children, _ := fs.ListRelatedHierarchy("Auth"); strings.Join(children, ", ") != "Logout" {
+		t.Errorf("Auth still contains %v", children)
+	}
+
+	// Moving under the current parent changes nothing
+	mustSucceed(t, fs.MoveIssue("Login", "Accounts"))
+	if parents, _ := fs.ListRelatedParents("Login", FILE_RELATIONSHIPS_HIERARCHY); strings.Join(parents, ", ") != "Accounts" {
+		t.Errorf("Login is under %v after moving it to its parent", parents)
+	}
+}
+
+func TestMoveIssueRejectsInvalidParents(t *testing.T) {
+	tests := []struct {
+		name   string
+		child  string
+		parent string
+		kind   error
+	}{
+		{"itself", "Auth", "Auth", ErrInvalid},
+		{"below a lower type", "Auth", "Login", ErrInvalid},
+		{"below the same type", "Login", "Logout", ErrInvalid},
+		{"below a missing issue", "Login", "Signup", ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := bootHierarchy(t)
+
+			moveErr := fs.MoveIssue(test.child, test.parent)
+			if !errors.Is(moveErr, test.kind) {
+				t.Fatalf("expected %v, got %v", test.kind, moveErr)
+			}
+
+			if parents, _ := fs.ListRelatedParents("Login", FILE_RELATIONSHIPS_HIERARCHY); strings.Join(parents, ", ") != "Auth" {
+				t.Errorf("rejected move left Login under %v", parents)
+			}
+		})
+	}
+}
+
+func TestMoveIssueRejectsCycles(t *testing.T) {
+	fs := bootHierarchy(t)
+
+	// Reordered types allow Launch below Login, but Login is already below Launch
+	mustSucceed(t, pmfile.SetFileTypes([]string{"task", "story", "epic"}))
+	t.Cleanup(func() { pmfile.SetFileTypes([]string{"epic", "story", "task"}) })
+
+	moveErr := fs.MoveIssue("Launch", "Login")
+	if !errors.Is(moveErr, ErrConflict) {
+		t.Fatalf("expected a conflict, got %v", moveErr)
+	}
+
+	if parents, _ := fs.ListRelatedParents("Launch", FILE_RELATIONSHIPS_HIERARCHY); len(parents) != 0 {
+		t.Errorf("rejected move put Launch under %v", parents)
+	}
+}
@@ -0,0 +1,321 @@
+package fileSystem
+
+import (
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/git"
+
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+/**
+Links commits to the issues their messages refer to with trailers such as
+Refs: Login form or Closes: PM-12. The index only caches what the history says, so
+it is kept in the git directory as pm-commits rather than in the committed .pm directory.
+ScanCommits brings it up to date and only reads the commits added since the last scan.
+*/
+
+const COMMIT_INDEX_FILE = "pm-commits"
+
+// Where the index was kept before it moved to the git directory, removed by ScanCommits
+const LEGACY_COMMIT_INDEX_FILE = "commits"
+
+type LinkedCommit struct {
+	Hash    string
+	Author  string
+	Date    string
+	Subject string
+	Closes  bool // The commit closes the issue rather than only referring to it
+}
+
+type commitIndex struct {
+	Heads  []string // Branch heads when the index was last updated
+	Issues map[string][]LinkedCommit
+}
+
+type ScanReport struct {
+	Commits int      // Commits read from the history
+	Links   int      // New links between a commit and an issue
+	Closed  []string // Issues moved to done by Closes trailers
+	Unknown []string // References that match no issue
+}
+
+func newCommitIndex() *commitIndex {
+	return &commitIndex{Issues: map[string][]LinkedCommit{}}
+}
+
+func (fs *FileSystem) commitIndexPath() (string, error) {
+	return git.GitPath(fs.root, COMMIT_INDEX_FILE)
+}
+
+// The index on disk, empty before the first scan and outside of a git repository
+func (fs *FileSystem) loadCommitIndex() (*commitIndex, error) {
+	path, pathErr := fs.commitIndexPath()
+	if pathErr != nil {
+		return newCommitIndex(), nil
+	}
+
+	file, openErr := os.Open(path)
+	if errors.Is(openErr, os.ErrNotExist) {
+		return newCommitIndex(), nil
+	}
+
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer file.Close()
+
+	index := newCommitIndex()
+	decodeErr := gob.NewDecoder(file).Decode(index)
+	if decodeErr != nil {
+		return nil, errors.New("Error reading " + path + ": " + decodeErr.Error())
+	}
+
+	return index, nil
+}
+
+func (fs *FileSystem) saveCommitIndex(index *commitIndex) error {
+	path, pathErr := fs.commitIndexPath()
+	if pathErr != nil {
+		return pathErr
+	}
+
+	file, createErr := os.Create(path)
+	if createErr != nil {
+		return createErr
+	}
+	defer file.Close()
+
+	encodeErr := gob.NewEncoder(file).Encode(index)
+	if encodeErr != nil {
+		return encodeErr
+	}
+
+	removeErr := os.Remove(filepath.Join(fs.root, LEGACY_COMMIT_INDEX_FILE))
+	if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+		return removeErr
+	}
+
+	return nil
+}
+
+// The issue a reference names: an issue name, the name in another case, or the key an
+// issue was imported from such as PM-12 for jira:PM-12 and #12 for github#12
+func (fs *FileSystem) ResolveReference(reference string) (string, bool) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return "", false
+	}
+
+	if fs.validateFileExists(reference) == nil {
+		return reference, true
+	}
+
+	files, filesErr := fs.ListAllFilesWithTypes()
+	if filesErr != nil {
+		return "", false
+	}
+
+	// Sorted so that the same reference always resolves to the same issue
+	var names []string
+	for _, fileNames := range files {
+		names = append(names, fileNames...)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.EqualFold(name, reference) {
+			return name, true
+		}
+	}
+
+	for _, name := range names {
+		source, ok := fs.GetFileMeta(name, pmfile.FILE_META_SOURCE)
+		if !ok {
+			continue
+		}
+
+		if strings.HasSuffix(source, ":"+reference) || (strings.HasPrefix(reference, "#") && strings.HasSuffix(source, reference)) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// A trailer names one issue, or several separated by commas.
+// Returns the issues found and the references that match none.
+func (fs *FileSystem) ResolveTrailer(value string) ([]string, []string) {
+	if name, ok := fs.ResolveReference(value); ok {
+		return []string{name}, nil
+	}
+
+	var names, unknown []string
+	for _, reference := range strings.Split(value, ",") {
+		if reference = strings.TrimSpace(reference); reference == "" {
+			continue
+		}
+
+		if name, ok := fs.ResolveReference(reference); ok {
+			names = append(names, name)
+		} else {
+			unknown = append(unknown, reference)
+		}
+	}
+
+	return names, unknown
+}
+
+// Adds the links of one commit to the index, returns the issues it closes
+func (fs *FileSystem) indexCommit(index *commitIndex, commit git.Commit, report *ScanReport) []string {
+	var closes []string
+	for _, trailer := range commit.Trailers {
+		names, unknown := fs.ResolveTrailer(trailer.Value)
+		if !trailer.Shared {
+			report.Unknown = append(report.Unknown, unknown...)
+		}
+
+		for _, name := range names {
+			closing := trailer.Key == git.TRAILER_CLOSES
+			if closing {
+				closes = append(closes, name)
+			}
+
+			linked := index.Issues[name]
+			known := false
+			for position, existing := range linked {
+				if existing.Hash == commit.Hash {
+					linked[position].Closes = existing.Closes || closing
+					known = true
+				}
+			}
+
+			if !known {
+				index.Issues[name] = append(linked, LinkedCommit{
+					Hash:    commit.Hash,
+					Author:  commit.Author,
+					Date:    commit.Date,
+					Subject: commit.Subject,
+					Closes:  closing,
+				})
+				report.Links++
+			}
+		}
+	}
+
+	return closes
+}
+
+// Reads the commits added to local branches since the last scan, or the whole history with
+// rescan. With close the issues named in Closes trailers of those commits are moved to done.
+func (fs *FileSystem) ScanCommits(rescan bool, close bool) (ScanReport, error) {
+	report := ScanReport{}
+
+	// The index only caches what the history says, an unreadable one is rebuilt
+	index, loadErr := fs.loadCommitIndex()
+	if loadErr != nil || rescan {
+		index = newCommitIndex()
+	}
+
+	scannedHeads := index.Heads
+
+	heads, headsErr := git.BranchHeads(fs.root)
+	if headsErr != nil {
+		return report, headsErr
+	}
+
+	// Commits rewritten since the last scan would stay linked, the index is rebuilt instead
+	for _, head := range index.Heads {
+		if !git.OnBranch(fs.root, head) {
+			index = newCommitIndex()
+			break
+		}
+	}
+
+	commits, logErr := git.Log(fs.root, index.Heads)
+	if logErr != nil {
+		return report, logErr
+	}
+
+	var closes []string
+	for _, commit := range commits {
+		closes = append(closes, fs.indexCommit(index, commit, &report)...)
+	}
+
+	report.Commits = len(commits)
+	index.Heads = heads
+
+	if close {
+		for _, name := range closes {
+			if fs.GetFileStatus(name) != pmfile.FILE_STATUS_DONE && indexOf(report.Closed, name) == -1 {
+				report.Closed = append(report.Closed, name)
+			}
+		}
+
+		statusErr := fs.SetIssuesStatus(report.Closed, pmfile.FILE_STATUS_DONE)
+		if statusErr != nil {
+			return report, statusErr
+		}
+	}
+
+	// Like the other stores the index is only written when it changed
+	if len(commits) == 0 && slices.Equal(scannedHeads, heads) {
+		return report, nil
+	}
+
+	return report, fs.saveCommitIndex(index)
+}
+
+// Commits linked to an issue, oldest first
+func (fs *FileSystem) LinkedCommits(fileName string) ([]LinkedCommit, error) {
+	existsErr := fs.validateFileExists(fileName)
+	if existsErr != nil {
+		return nil, existsErr
+	}
+
+	index, loadErr := fs.loadCommitIndex()
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	return index.Issues[fileName], nil
+}
+
+// The issue being worked on: the issue a branch like feature/login-form is named after,
+// or else the only issue of the lowest type that is in progress
+func (fs *FileSystem) CurrentIssue(branch string) (string, bool) {
+	base := branch[strings.LastIndex(branch, "/")+1:]
+	for _, candidate := range []string{branch, base, strings.ReplaceAll(base, "-", " ")} {
+		if name, ok := fs.ResolveReference(candidate); ok {
+			return name, true
+		}
+	}
+
+	if len(pmfile.FILE_TYPE_HIERARCHY) == 0 {
+		return "", false
+	}
+
+	lowestType := pmfile.FILE_TYPE_HIERARCHY[len(pmfile.FILE_TYPE_HIERARCHY)-1]
+	fileNames, listErr := fs.ListFileNamesByType(lowestType)
+	if listErr != nil {
+		return "", false
+	}
+
+	var inProgress []string
+	for _, fileName := range fileNames {
+		if fs.GetFileStatus(fileName) == pmfile.FILE_STATUS_IN_PROGRESS {
+			inProgress = append(inProgress, fileName)
+		}
+	}
+
+	if len(inProgress) != 1 {
+		return "", false
+	}
+
+	return inProgress[0], true
+}
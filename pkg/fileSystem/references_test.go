@@ -0,0 +1,161 @@
+package fileSystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/git"
+)
+
+// The issues of bootHierarchy in a new git repository
+func bootRepository(t *testing.T) *FileSystem {
+	t.Helper()
+
+	t.Setenv("GIT_AUTHOR_NAME", "pm")
+	t.Setenv("GIT_AUTHOR_EMAIL", "pm@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "pm")
+	t.Setenv("GIT_COMMITTER_EMAIL", "pm@example.com")
+
+	fs := bootHierarchy(t)
+	_, initErr := git.Run(".", "init", "--quiet")
+	mustSucceed(t, initErr)
+
+	return fs
+}
+
+func commit(t *testing.T, message string) {
+	t.Helper()
+
+	_, commitErr := git.Run(".", "commit", "--quiet", "--allow-empty", "--message", message)
+	mustSucceed(t, commitErr)
+}
+
+func TestResolveReference(t *testing.T) {
+	fs := bootHierarchy(t)
+	mustSucceed(t, fs.SetFileMeta("Login", pmfile.FILE_META_SOURCE, "jira:PM-12"))
+	mustSucceed(t, fs.SetFileMeta("Logout", pmfile.FILE_META_SOURCE, "github#7"))
+
+	tests := []struct {
+		reference string
+		name      string
+	}{
+		{"Login", "Login"},
+		{" Auth ", "Auth"},
+		{"launch", "Launch"},
+		{"PM-12", "Login"},
+		{"#7", "Logout"},
+		{"7", ""},
+		{"PM-1", ""},
+		{"Signup", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		name, ok := fs.ResolveReference(test.reference)
+		if name != test.name || ok != (test.name != "") {
+			t.Errorf("%q resolves to %q, %v, want %q", test.reference, name, ok, test.name)
+		}
+	}
+
+	names, unknown := fs.ResolveTrailer("Login, PM-12 ,Signup")
+	if strings.Join(names, ", ") != "Login, Login" || strings.Join(unknown, ", ") != "Signup" {
+		t.Errorf("trailer resolves to %v and leaves %v", names, unknown)
+	}
+}
+
+func TestScanCommits(t *testing.T) {
+	fs := bootRepository(t)
+	commit(t, "Add the form\n\nRefs: Login")
+	commit(t, "Finish logging out\n\nCloses: Logout, Signup")
+	commit(t, "Tidy readme")
+
+	// Left behind by versions that kept the index in the project
+	legacyIndex := filepath.Join(fs.root, LEGACY_COMMIT_INDEX_FILE)
+	mustSucceed(t, os.WriteFile(legacyIndex, []byte("stale"), 0644))
+
+	report, scanErr := fs.ScanCommits(false, true)
+	mustSucceed(t, scanErr)
+	if report.Commits != 3 || report.Links != 2 || strings.Join(report.Closed, ", ") != "Logout" || strings.Join(report.Unknown, ", ") != "Signup" {
+		t.Errorf("first scan reported %+v", report)
+	}
+
+	if status := fs.GetFileStatus("Logout"); status != pmfile.FILE_STATUS_DONE {
+		t.Errorf("Logout is %s, want done", status)
+	}
+
+	linked, linkedErr := fs.LinkedCommits("Login")
+	mustSucceed(t, linkedErr)
+	if len(linked) != 1 || linked[0].Subject != "Add the form" || linked[0].Closes {
+		t.Errorf("Login is linked to %+v", linked)
+	}
+
+	// The index is kept out of the project so scans leave git status clean
+	if _, statErr := os.Stat(legacyIndex); !errors.Is(statErr, os.ErrNotExist) {
+		t.Error("the scan kept the index in the project")
+	}
+
+	if _, statErr := os.Stat(filepath.Join(".git", COMMIT_INDEX_FILE)); statErr != nil {
+		t.Errorf("the index is not in the git directory: %v", statErr)
+	}
+
+	report, scanErr = fs.ScanCommits(false, true)
+	mustSucceed(t, scanErr)
+	if report.Commits != 0 || report.Links != 0 {
+		t.Errorf("scan without new commits reported %+v", report)
+	}
+
+	commit(t, "Remember the session\n\nRefs: login")
+	report, scanErr = fs.ScanCommits(false, false)
+	mustSucceed(t, scanErr)
+	if report.Commits != 1 || report.Links != 1 {
+		t.Errorf("scan of one new commit reported %+v", report)
+	}
+
+	report, scanErr = fs.ScanCommits(true, false)
+	mustSucceed(t, scanErr)
+	if report.Commits != 4 || report.Links != 3 {
+		t.Errorf("rescan reported %+v", report)
+	}
+
+	if linked, _ := fs.LinkedCommits("Login"); len(linked) != 2 {
+		t.Errorf("rescan linked Login to %+v", linked)
+	}
+}
+
+func TestCurrentIssue(t *testing.T) {
+	fs := bootHierarchy(t)
+	mustSucceed(t, fs.CreateFile("Login form", "task"))
+
+	tests := []struct {
+		branch string
+		name   string
+	}{
+		{"Login", "Login"},
+		{"feature/logout", "Logout"},
+		{"feature/login-form", "Login form"},
+		{"fix/signup", ""},
+	}
+
+	for _, test := range tests {
+		name, ok := fs.CurrentIssue(test.branch)
+		if name != test.name || ok != (test.name != "") {
+			t.Errorf("%q names %q, %v, want %q", test.branch, name, ok, test.name)
+		}
+	}
+
+	// Without an issue named after the branch, the only task in progress
+	mustSucceed(t, fs.SetFileStatus("Logout", pmfile.FILE_STATUS_IN_PROGRESS))
+	mustSucceed(t, fs.SetFileStatus("Auth", pmfile.FILE_STATUS_IN_PROGRESS))
+	if name, ok := fs.CurrentIssue("main"); !ok || name != "Logout" {
+		t.Errorf("main names %q, %v, want Logout", name, ok)
+	}
+
+	mustSucceed(t, fs.SetFileStatus("Login", pmfile.FILE_STATUS_IN_PROGRESS))
+	if name, ok := fs.CurrentIssue("main"); ok {
+		t.Errorf("main names %q while two tasks are in progress", name)
+	}
+}
@@ -0,0 +1,38 @@
+package fileSystem
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Directory holding the issues of a project
+const PROJECT_DIRECTORY = ".pm"
+
+// Walks up from start to the first directory containing .pm, the way git finds .git.
+// Returns the absolute path of that .pm directory.
+func FindProjectRoot(start string) (string, error) {
+	directory, absErr := filepath.Abs(start)
+	if absErr != nil {
+		return "", absErr
+	}
+
+	for {
+		root := filepath.Join(directory, PROJECT_DIRECTORY)
+		if IsProjectRoot(root) {
+			return root, nil
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return "", NotFoundError("No pm project found in " + start + " or any parent directory, run pm init to create one")
+		}
+
+		directory = parent
+	}
+}
+
+// Follows symlinks, pm attach links .pm to a shared directory
+func IsProjectRoot(root string) bool {
+	info, statErr := os.Stat(root)
+	return statErr == nil && info.IsDir()
+}
@@ -0,0 +1,154 @@
+package fileSystem
+
+import (
+	pmfile "github/pm/pkg/file"
+
+	"errors"
+	"sort"
+)
+
+// An issue that can be worked on now
+type ReadyIssue struct {
+	Name     string
+	Type     string
+	Priority string
+	Depth    int // Length of the longest chain of upstream dependencies
+}
+
+// Orders every issue so that upstream dependencies come before the issues
+// that depend on them. Returns the order and the depth of every issue.
+func (fs *FileSystem) TopologicalDependencyOrder() ([]string, map[string]int, error) {
+	fileTree := fs.getFileTree()
+
+	inDegree := map[string]int{}
+	for id := range fileTree.Vertices {
+		inDegree[id] = 0
+	}
+
+	for id := range fileTree.Vertices {
+		for _, edge := range fileTree.Vertices[id].Children {
+			if edge.Label != FILE_RELATIONSHIP_DEPENDENCY || !fileTree.HasVertex(edge.To.ID) {
+				continue
+			}
+
+			inDegree[edge.To.ID]++
+		}
+	}
+
+	var queue []string
+	for id, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, id)
+		}
+	}
+	sort.Strings(queue)
+
+	depth := map[string]int{}
+	order := make([]string, 0, len(inDegree))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		order = append(order, id)
+
+		var released []string
+		for _, edge := range fileTree.Vertices[id].Children {
+			if edge.Label != FILE_RELATIONSHIP_DEPENDENCY || !fileTree.HasVertex(edge.To.ID) {
+				continue
+			}
+
+			downstream := edge.To.ID
+			depth[downstream] = max(depth[downstream], depth[id]+1)
+			inDegree[downstream]--
+			if inDegree[downstream] == 0 {
+				released = append(released, downstream)
+			}
+		}
+
+		sort.Strings(released)
+		queue = append(queue, released...)
+	}
+
+	if len(order) != len(inDegree) {
+		return nil, nil, errors.New("Dependencies contain a cycle")
+	}
+
+	return order, depth, nil
+}
+
+// Checks if the issue, or any of its hierarchy ancestors, waits on an
+// upstream dependency that is not done yet
+func (fs *FileSystem) IsBlocked(fileName string) bool {
+	upstreams, upstreamErr := fs.ListRelatedParentDependency(fileName)
+	if upstreamErr != nil {
+		return true
+	}
+
+	for _, upstream := range upstreams {
+		if fs.GetFileStatus(upstream) != pmfile.FILE_STATUS_DONE {
+			return true
+		}
+	}
+
+	parents, parentsErr := fs.ListRelatedParents(fileName, FILE_RELATIONSHIPS_HIERARCHY)
+	if parentsErr != nil {
+		return true
+	}
+
+	for _, parent := range parents {
+		if fs.IsBlocked(parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lists issues of the lowest type that are not done and are not blocked by unfinished
+// upstream dependencies, ordered by priority and then by depth in the dependency graph.
+// Epics and stories without children are plans rather than work and are left out.
+func (fs *FileSystem) ListReadyIssues() ([]ReadyIssue, error) {
+	order, depth, orderErr := fs.TopologicalDependencyOrder()
+	if orderErr != nil {
+		return nil, orderErr
+	}
+
+	workType := pmfile.FILE_TYPE_HIERARCHY[len(pmfile.FILE_TYPE_HIERARCHY)-1]
+	var ready []ReadyIssue
+	for _, fileName := range order {
+		fileType, typeErr := fs.GetFileType(fileName)
+		if typeErr != nil {
+			return nil, typeErr
+		}
+
+		if fileType != workType {
+			continue
+		}
+
+		if fs.GetFileStatus(fileName) == pmfile.FILE_STATUS_DONE || fs.IsBlocked(fileName) {
+			continue
+		}
+
+		ready = append(ready, ReadyIssue{
+			Name:     fileName,
+			Type:     fileType,
+			Priority: fs.GetFilePriority(fileName),
+			Depth:    depth[fileName],
+		})
+	}
+
+	sort.SliceStable(ready, func(i, j int) bool {
+		iPriority := indexOf(pmfile.FILE_PRIORITIES, ready[i].Priority)
+		jPriority := indexOf(pmfile.FILE_PRIORITIES, ready[j].Priority)
+		if iPriority != jPriority {
+			return iPriority < jPriority
+		}
+
+		if ready[i].Depth != ready[j].Depth {
+			return ready[i].Depth < ready[j].Depth
+		}
+
+		return ready[i].Name < ready[j].Name
+	})
+
+	return ready, nil
+}
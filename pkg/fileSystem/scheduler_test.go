@@ -0,0 +1,92 @@
+package fileSystem
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopologicalDependencyOrder(t *testing.T) {
+	fs := bootInTempDir(t)
+	for _, name := range []string{"Deploy", "Schema", "Api", "Ui"} {
+		mustSucceed(t, fs.CreateFile(name, "task"))
+	}
+
+	// Schema blocks Api and Ui, both block Deploy
+	mustSucceed(t, fs.LinkDependency("Schema", "Api"))
+	mustSucceed(t, fs.LinkDependency("Schema", "Ui"))
+	mustSucceed(t, fs.LinkDependency("Api", "Deploy"))
+	mustSucceed(t, fs.LinkDependency("Ui", "Deploy"))
+
+	order, depth, orderErr := fs.TopologicalDependencyOrder()
+	mustSucceed(t, orderErr)
+
+	if got := strings.Join(order, ", "); got != "Schema, Api, Ui, Deploy" {
+		t.Errorf("order is %s", got)
+	}
+
+	for name, want := range map[string]int{"Schema": 0, "Api": 1, "Ui": 1, "Deploy": 2} {
+		if depth[name] != want {
+			t.Errorf("%s has depth %d, want %d", name, depth[name], want)
+		}
+	}
+}
+
+func TestIsBlocked(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Billing", "story"))
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Invoices", "task"))
+	mustSucceed(t, fs.LinkHierarchy("Billing", "Invoices"))
+	mustSucceed(t, fs.LinkDependency("Auth", "Billing"))
+
+	// Waiting on the dependency of a hierarchy ancestor blocks the child as well
+	if !fs.IsBlocked("Invoices") || !fs.IsBlocked("Billing") {
+		t.Error("Billing and Invoices should wait on Auth")
+	}
+
+	if fs.IsBlocked("Auth") {
+		t.Error("Auth has no upstream dependencies")
+	}
+
+	mustSucceed(t, fs.SetFileStatus("Auth", "done"))
+	if fs.IsBlocked("Invoices") {
+		t.Error("Invoices should be unblocked once Auth is done")
+	}
+}
+
+func TestListReadyIssuesOrder(t *testing.T) {
+	fs := bootInTempDir(t)
+	mustSucceed(t, fs.CreateFile("Auth", "story"))
+	mustSucceed(t, fs.CreateFile("Billing", "story"))
+	for _, name := range []string{"Cache", "Login", "Logout", "Sessions", "Signup", "Styles", "Tokens"} {
+		mustSucceed(t, fs.CreateFile(name, "task"))
+	}
+
+	mustSucceed(t, fs.LinkHierarchy("Auth", "Login"))
+	mustSucceed(t, fs.LinkDependency("Tokens", "Sessions"))
+	mustSucceed(t, fs.LinkDependency("Sessions", "Logout"))
+	mustSucceed(t, fs.LinkDependency("Signup", "Cache"))
+	mustSucceed(t, fs.SetFileStatus("Tokens", "done"))
+	mustSucceed(t, fs.SetFileStatus("Signup", "done"))
+	mustSucceed(t, fs.SetFilePriority("Sessions", "high"))
+	mustSucceed(t, fs.SetFilePriority("Styles", "low"))
+
+	ready, readyErr := fs.ListReadyIssues()
+	mustSucceed(t, readyErr)
+
+	// Only tasks are work, so Auth and the empty Billing story are left out with
+	// Logout, which waits on Sessions, and the done issues.
+	// Higher priorities come first, then issues with fewer upstream dependencies.
+	var names []string
+	for _, issue := range ready {
+		names = append(names, issue.Name)
+	}
+
+	if got := strings.Join(names, ", "); got != "Sessions, Login, Cache, Styles" {
+		t.Errorf("ready issues are %s", got)
+	}
+
+	if invalidErr := fs.SetFilePriority("Login", "urgent"); invalidErr == nil {
+		t.Error("unknown priorities should be rejected")
+	}
+}
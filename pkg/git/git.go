@@ -0,0 +1,78 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+/**
+Thin wrapper around the local git binary. Every command runs with -C so that
+pm works on the repository containing a directory rather than the working directory.
+*/
+
+// Runs git in dir and returns its output without the trailing newline
+func Run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if runErr != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = runErr.Error()
+		}
+
+		return "", errors.New("git " + args[0] + ": " + message)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// Top level directory of the work tree containing dir
+func TopLevel(dir string) (string, error) {
+	return Run(dir, "rev-parse", "--show-toplevel")
+}
+
+// Path of name in the git directory of the repository containing dir, outside of the work tree
+func GitPath(dir string, name string) (string, error) {
+	path, pathErr := Run(dir, "rev-parse", "--git-path", name)
+	if pathErr != nil {
+		return "", pathErr
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	return path, nil
+}
+
+// Stages every change below dir and commits only those paths, changes staged elsewhere
+// in the repository are left alone. Returns false when there was nothing to commit.
+func CommitDirectory(dir string, message string) (bool, error) {
+	_, addErr := Run(dir, "add", "--all", "--", ".")
+	if addErr != nil {
+		return false, addErr
+	}
+
+	status, statusErr := Run(dir, "status", "--porcelain", "--", ".")
+	if statusErr != nil {
+		return false, statusErr
+	}
+
+	if status == "" {
+		return false, nil
+	}
+
+	_, commitErr := Run(dir, "commit", "--quiet", "--message", message, "--", ".")
+	if commitErr != nil {
+		return false, commitErr
+	}
+
+	return true, nil
+}
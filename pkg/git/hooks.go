@@ -0,0 +1,118 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const HOOK_PREPARE_COMMIT_MSG = "prepare-commit-msg"
+const HOOK_COMMIT_MSG = "commit-msg"
+const HOOK_POST_COMMIT = "post-commit"
+
+var HOOKS = []string{HOOK_PREPARE_COMMIT_MSG, HOOK_COMMIT_MSG, HOOK_POST_COMMIT}
+
+// Marks the hooks pm wrote, hooks without it are never changed unless forced
+const HOOK_MARKER = "# Installed by pm hooks install"
+
+var ErrForeignHook = errors.New("hook was not installed by pm")
+
+// Hooks directory of the repository containing dir, honouring core.hooksPath
+func HooksDirectory(dir string) (string, error) {
+	hooks, hooksErr := Run(dir, "rev-parse", "--git-path", "hooks")
+	if hooksErr != nil {
+		return "", hooksErr
+	}
+
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(dir, hooks)
+	}
+
+	return hooks, nil
+}
+
+// Shell script running pm hook name, command is pm and its environment quoted for the shell
+func HookScript(command string, name string, flags ...string) string {
+	return "#!/bin/sh\n" + HOOK_MARKER + "\nexec " + strings.Join(append([]string{command, "hook", name}, flags...), " ") + " \"$@\"\n"
+}
+
+// Quotes value for a POSIX shell
+func ShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func isPmHook(path string) (bool, bool) {
+	content, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return false, false
+	}
+
+	return true, strings.Contains(string(content), HOOK_MARKER)
+}
+
+// Writes the hook scripts, by hook name, into the repository containing dir and returns
+// the hooks directory. Nothing is written if a hook pm didn't write is in the way, unless forced.
+func InstallHooks(dir string, scripts map[string]string, force bool) (string, error) {
+	hooks, hooksErr := HooksDirectory(dir)
+	if hooksErr != nil {
+		return "", hooksErr
+	}
+
+	for name := range scripts {
+		path := filepath.Join(hooks, name)
+		if exists, ours := isPmHook(path); exists && !ours && !force {
+			return hooks, fmt.Errorf("%s already exists and %w, keep it or replace it with --force", path, ErrForeignHook)
+		}
+	}
+
+	mkdirErr := os.MkdirAll(hooks, os.ModePerm)
+	if mkdirErr != nil {
+		return hooks, mkdirErr
+	}
+
+	for name, script := range scripts {
+		writeErr := os.WriteFile(filepath.Join(hooks, name), []byte(script), 0755)
+		if writeErr != nil {
+			return hooks, writeErr
+		}
+	}
+
+	return hooks, nil
+}
+
+// Removes the hooks pm installed, returns the names of the removed hooks
+func UninstallHooks(dir string, names []string) ([]string, error) {
+	hooks, hooksErr := HooksDirectory(dir)
+	if hooksErr != nil {
+		return nil, hooksErr
+	}
+
+	var removed []string
+	for _, name := range names {
+		path := filepath.Join(hooks, name)
+		if _, ours := isPmHook(path); !ours {
+			continue
+		}
+
+		removeErr := os.Remove(path)
+		if removeErr != nil {
+			return removed, removeErr
+		}
+
+		removed = append(removed, name)
+	}
+
+	return removed, nil
+}
+
+// Short name of the checked out branch, also before its first commit
+func CurrentBranch(dir string) (string, error) {
+	return Run(dir, "symbolic-ref", "--short", "HEAD")
+}
+
+// Puts a trailer below the empty subject of a message that is still being written
+func PrefillTrailer(message string, key string, value string) string {
+	return "\n\n" + key + ": " + value + "\n" + message
+}
@@ -0,0 +1,133 @@
+package git
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Trailers naming the issues a commit works on, matched without regard to case
+const TRAILER_REFS = "refs"
+const TRAILER_CLOSES = "closes"
+
+// Fixes and Resolves close issues as well, but are shared with other conventions
+// such as Fixes: <commit> or a sentence about what was fixed
+var sharedTrailers = map[string]bool{"fixes": true, "resolves": true}
+
+var trailerKeys = map[string]string{
+	"refs":       TRAILER_REFS,
+	"references": TRAILER_REFS,
+	"closes":     TRAILER_CLOSES,
+	"fixes":      TRAILER_CLOSES,
+	"resolves":   TRAILER_CLOSES,
+}
+
+var trailerPattern = regexp.MustCompile(`^([A-Za-z-]+)\s*:\s*(.+)$`)
+
+type Trailer struct {
+	Key    string // TRAILER_REFS or TRAILER_CLOSES
+	Value  string
+	Shared bool // Written as Fixes or Resolves, the value may not name issues at all
+}
+
+type Commit struct {
+	Hash     string
+	Author   string
+	Date     string // Author date in RFC 3339
+	Subject  string
+	Trailers []Trailer
+}
+
+// Fields are separated by the ascii unit separator and commits by the record separator.
+// git picks the trailers out of the last paragraph of the message and unfolds them.
+const logFormat = "%H%x1f%an%x1f%aI%x1f%s%x1f%(trailers:only,unfold)%x1e"
+
+// Commits of every local branch from oldest to newest, leaving out the ones reachable from exclude
+func Log(dir string, exclude []string) ([]Commit, error) {
+	args := []string{"log", "--branches", "--reverse", "--format=" + logFormat}
+	if len(exclude) > 0 {
+		args = append(append(args, "--not"), exclude...)
+	}
+
+	out, logErr := Run(dir, args...)
+	if logErr != nil {
+		return nil, logErr
+	}
+
+	return parseLog(out), nil
+}
+
+func parseLog(out string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 5)
+		if len(fields) != 5 {
+			continue
+		}
+
+		commits = append(commits, Commit{
+			Hash:     fields[0],
+			Author:   fields[1],
+			Date:     fields[2],
+			Subject:  fields[3],
+			Trailers: parseTrailers(fields[4]),
+		})
+	}
+
+	return commits
+}
+
+// Heads of the local branches, what Log has seen once it ran
+func BranchHeads(dir string) ([]string, error) {
+	out, refsErr := Run(dir, "for-each-ref", "--format=%(objectname)", "refs/heads")
+	if refsErr != nil {
+		return nil, refsErr
+	}
+
+	if out == "" {
+		return nil, nil
+	}
+
+	return strings.Split(out, "\n"), nil
+}
+
+// Trailers of the message file a commit hook was given. git decides what the trailer
+// paragraph is, lines like Fixes: in the middle of the body are prose.
+func MessageTrailers(dir string, messageFile string) ([]Trailer, error) {
+	path, pathErr := filepath.Abs(messageFile)
+	if pathErr != nil {
+		return nil, pathErr
+	}
+
+	out, parseErr := Run(dir, "interpret-trailers", "--parse", path)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return parseTrailers(out), nil
+}
+
+// Keeps the trailers pm knows from unfolded trailer lines as git prints them
+func parseTrailers(lines string) []Trailer {
+	var trailers []Trailer
+	for _, line := range strings.Split(lines, "\n") {
+		match := trailerPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		token := strings.ToLower(match[1])
+		key, ok := trailerKeys[token]
+		if ok {
+			trailers = append(trailers, Trailer{Key: key, Value: strings.TrimSpace(match[2]), Shared: sharedTrailers[token]})
+		}
+	}
+
+	return trailers
+}
+
+// Whether a local branch contains the commit, false once it was amended or rebased away
+func OnBranch(dir string, hash string) bool {
+	out, branchErr := Run(dir, "branch", "--contains", hash, "--format=%(refname)")
+	return branchErr == nil && out != ""
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Creates a repository with an empty commit for every message, oldest first
+func commitMessages(t *testing.T, messages ...string) string {
+	t.Helper()
+
+	t.Setenv("GIT_AUTHOR_NAME", "pm")
+	t.Setenv("GIT_AUTHOR_EMAIL", "pm@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "pm")
+	t.Setenv("GIT_COMMITTER_EMAIL", "pm@example.com")
+
+	repo := t.TempDir()
+	if _, initErr := Run(repo, "init", "--quiet"); initErr != nil {
+		t.Fatal(initErr)
+	}
+
+	for _, message := range messages {
+		if _, commitErr := Run(repo, "commit", "--quiet", "--allow-empty", "--cleanup=verbatim", "--message", message); commitErr != nil {
+			t.Fatal(commitErr)
+		}
+	}
+
+	return repo
+}
+
+// Trailers as "key value", joined with "; "
+func formatTrailers(trailers []Trailer) string {
+	var formatted []string
+	for _, trailer := range trailers {
+		formatted = append(formatted, trailer.Key+" "+trailer.Value)
+	}
+
+	return strings.Join(formatted, "; ")
+}
+
+func TestLogReadsOnlyTheTrailerParagraph(t *testing.T) {
+	tests := []struct {
+		message  string
+		subject  string
+		trailers string
+	}{
+		{"Keep sessions alive\n\nCloses: Sessions", "Keep sessions alive", "closes Sessions"},
+		{"Tidy readme\n\nFixes: the typo in the middle of the body\nwas reported twice.", "Tidy readme", ""},
+		{"Tidy readme\n\nRefs: Login\n\nThe rest of the body.", "Tidy readme", ""},
+		{"Split login\n\nMoves the form.\n\nRefs: Login,\n  Logout\nFixes: Sessions\nSigned-off-by: pm <pm@example.com>", "Split login", "refs Login, Logout; closes Sessions"},
+		{"Refs: Login", "Refs: Login", ""},
+	}
+
+	messages := []string{}
+	for _, test := range tests {
+		messages = append(messages, test.message)
+	}
+
+	commits, logErr := Log(commitMessages(t, messages...), nil)
+	if logErr != nil {
+		t.Fatal(logErr)
+	}
+
+	if len(commits) != len(tests) {
+		t.Fatalf("expected %d commits, got %d", len(tests), len(commits))
+	}
+
+	for index, test := range tests {
+		if commits[index].Subject != test.subject {
+			t.Errorf("%q has the subject %q, want %q", test.message, commits[index].Subject, test.subject)
+		}
+
+		if trailers := formatTrailers(commits[index].Trailers); trailers != test.trailers {
+			t.Errorf("%q has the trailers %q, want %q", test.message, trailers, test.trailers)
+		}
+	}
+}
+
+func TestMessageTrailersSkipsComments(t *testing.T) {
+	repo := commitMessages(t)
+	messageFile := filepath.Join(repo, "COMMIT_EDITMSG")
+	message := "Split login\n\nRefs: Login\n# Please enter the commit message\n" +
+		"# ------------------------ >8 ------------------------\nCloses: Logout\n"
+	if writeErr := os.WriteFile(messageFile, []byte(message), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	trailers, trailersErr := MessageTrailers(repo, messageFile)
+	if trailersErr != nil {
+		t.Fatal(trailersErr)
+	}
+
+	if formatted := formatTrailers(trailers); formatted != "refs Login" {
+		t.Errorf("trailers are %q, want %q", formatted, "refs Login")
+	}
+}
@@ -0,0 +1,156 @@
+package importer
+
+import (
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/**
+Reads the issue list returned by the GitHub REST API, as saved by
+`gh api --paginate repos/<owner>/<repo>/issues?state=all > issues.json`.
+
+GitHub has no issue types so they come from labels named epic, story or task.
+Relationships are read from sub-issue parents and from references in issue bodies:
+
+	blocked by #3, depends on #3   #3 blocks this issue
+	blocks #3                      this issue blocks #3
+	part of #3, parent: #3         #3 contains this issue
+	- [ ] #3                       this issue contains #3
+*/
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubParent struct {
+	Number int `json:"number"`
+}
+
+type githubIssue struct {
+	Number         int             `json:"number"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body"`
+	State          string          `json:"state"`
+	Labels         []githubLabel   `json:"labels"`
+	Parent         *githubParent   `json:"parent"`
+	ParentIssueUrl string          `json:"parent_issue_url"`
+	PullRequest    json.RawMessage `json:"pull_request"`
+}
+
+var githubBlockedBy = regexp.MustCompile(`(?i)\b(?:blocked by|depends on)\s+#(\d+)`)
+var githubBlocks = regexp.MustCompile(`(?i)\bblocks\s+#(\d+)`)
+var githubPartOf = regexp.MustCompile(`(?i)\b(?:part of|parent:?)\s+#(\d+)`)
+var githubTaskList = regexp.MustCompile(`^\s*[-*]\s+\[[ xX]\]\s+#(\d+)\b`)
+var githubIssueUrl = regexp.MustCompile(`/issues/(\d+)$`)
+
+func githubId(number string) string {
+	return "#" + number
+}
+
+func ConvertGithub(data []byte) (Report, error) {
+	var issues []githubIssue
+	unmarshalErr := json.Unmarshal(data, &issues)
+	if unmarshalErr != nil {
+		return Report{}, errors.New("Invalid GitHub issues file: " + unmarshalErr.Error())
+	}
+
+	builder := newProjectBuilder()
+	labelTypes := map[string]string{}
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+			continue
+		}
+
+		id := githubId(strconv.Itoa(issue.Number))
+		if issue.Parent != nil {
+			builder.addEdge(githubId(strconv.Itoa(issue.Parent.Number)), id, fileSystem.FILE_RELATIONSHIPS_HIERARCHY)
+		} else if match := githubIssueUrl.FindStringSubmatch(issue.ParentIssueUrl); match != nil {
+			builder.addEdge(githubId(match[1]), id, fileSystem.FILE_RELATIONSHIPS_HIERARCHY)
+		}
+
+		for _, line := range strings.Split(issue.Body, "\n") {
+			for _, match := range githubBlockedBy.FindAllStringSubmatch(line, -1) {
+				builder.addEdge(githubId(match[1]), id, fileSystem.FILE_RELATIONSHIP_DEPENDENCY)
+			}
+
+			for _, match := range githubBlocks.FindAllStringSubmatch(line, -1) {
+				builder.addEdge(id, githubId(match[1]), fileSystem.FILE_RELATIONSHIP_DEPENDENCY)
+			}
+
+			for _, match := range githubPartOf.FindAllStringSubmatch(line, -1) {
+				builder.addEdge(githubId(match[1]), id, fileSystem.FILE_RELATIONSHIPS_HIERARCHY)
+			}
+
+			if match := githubTaskList.FindStringSubmatch(line); match != nil {
+				builder.addEdge(id, githubId(match[1]), fileSystem.FILE_RELATIONSHIPS_HIERARCHY)
+			}
+		}
+
+		for _, label := range issue.Labels {
+			name := strings.ToLower(label.Name)
+			if name == "subtask" || name == "sub-task" {
+				name = pmfile.FILE_TYPE_TASK
+			}
+
+			if indexOf(pmfile.FILE_TYPE_HIERARCHY, name) != -1 {
+				labelTypes[id] = name
+				break
+			}
+		}
+
+		status := pmfile.FILE_STATUS_TODO
+		if strings.EqualFold(issue.State, "closed") {
+			status = pmfile.FILE_STATUS_DONE
+		}
+
+		builder.addIssue(externalIssue{
+			id:     id,
+			title:  issue.Title,
+			body:   githubBody(issue.Title, issue.Body),
+			status: status,
+			source: "github" + id,
+		})
+	}
+
+	// Types of unlabelled issues depend on the edges read after them
+	for index := range builder.issues {
+		issue := &builder.issues[index]
+		issue.fileType = labelTypes[issue.id]
+		if issue.fileType != "" {
+			continue
+		}
+
+		issue.fileType = pmfile.FILE_TYPE_TASK
+		if builder.hasChildren(issue.id) {
+			issue.fileType = pmfile.FILE_TYPE_STORY
+		}
+	}
+
+	return builder.report(), nil
+}
+
+// GitHub bodies already are markdown, only the title heading pm issues start with is added
+func githubBody(title string, body string) string {
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if body == "" {
+		return "# " + title + "\n"
+	}
+
+	return "# " + title + "\n\n" + body + "\n"
+}
+
+func indexOf(values []string, value string) int {
+	for index, candidate := range values {
+		if candidate == value {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+// Issues as "source type status name" and edges as "from LABEL to", in report order
+func summarize(report Report) ([]string, []string) {
+	var issues, edges []string
+	for _, issue := range report.Project.Issues {
+		status := issue.Meta["status"]
+		if status == "" {
+			status = "todo"
+		}
+
+		issues = append(issues, issue.Meta["source"]+" "+issue.Type+" "+status+" "+issue.Name)
+	}
+
+	for _, edge := range report.Project.Edges {
+		edges = append(edges, edge.From+" "+edge.Label+" "+edge.To)
+	}
+
+	return issues, edges
+}
+
+func TestConvertGithub(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		issues []string
+		edges  []string
+	}{
+		{
+			name: "types from labels and children",
+			input: `[
+				{"number": 1, "title": "Launch", "state": "open", "labels": [{"name": "Epic"}]},
+				{"number": 2, "title": "Auth", "state": "open", "body": "- [ ] #3"},
+				{"number": 3, "title": "Login", "state": "closed", "labels": [{"name": "sub-task"}]},
+				{"number": 4, "title": "Logout", "state": "open"}
+			]`,
+			issues: []string{
+				"github#1 epic todo Launch",
+				"github#2 story todo Auth",
+				"github#3 task done Login",
+				"github#4 task todo Logout",
+			},
+			edges: []string{"Auth HIERARCHY Login"},
+		},
+		{
+			name: "sanitized and renamed names",
+			input: `[
+				{"number": 1, "title": "CI/CD pipeline", "state": "open"},
+				{"number": 2, "title": "  ", "state": "open"},
+				{"number": 3, "title": "Login", "state": "open"},
+				{"number": 4, "title": "Login", "state": "open"}
+			]`,
+			issues: []string{
+				"github#1 task todo CI-CD pipeline",
+				"github#2 task todo #2",
+				"github#3 task todo Login",
+				"github#4 task todo Login (#4)",
+			},
+		},
+		{
+			name: "links from parents and bodies",
+			input: `[
+				{"number": 1, "title": "Launch", "state": "open", "labels": [{"name": "epic"}]},
+				{"number": 2, "title": "Auth", "state": "open", "parent": {"number": 1}},
+				{"number": 3, "title": "Login", "state": "open", "body": "Part of #2\r\nBlocked by #4", "labels": [{"name": "task"}]},
+				{"number": 4, "title": "Sessions", "state": "open", "parent_issue_url": "https://api.github.com/repos/o/r/issues/2", "labels": [{"name": "task"}]},
+				{"number": 5, "title": "Tokens", "state": "open", "body": "blocks #4, blocks #9", "labels": [{"name": "task"}]}
+			]`,
+			issues: []string{
+				"github#1 epic todo Launch",
+				"github#2 story todo Auth",
+				"github#3 task todo Login",
+				"github#4 task todo Sessions",
+				"github#5 task todo Tokens",
+			},
+			edges: []string{
+				"Launch HIERARCHY Auth",
+				"Auth HIERARCHY Login",
+				"Sessions DEPENDENCY Login",
+				"Auth HIERARCHY Sessions",
+				"Tokens DEPENDENCY Sessions",
+			},
+		},
+		{
+			name: "pull requests are skipped",
+			input: `[
+				{"number": 1, "title": "Login", "state": "open"},
+				{"number": 2, "title": "Add login", "state": "open", "pull_request": {"url": "https://api.github.com/repos/o/r/pulls/2"}}
+			]`,
+			issues: []string{"github#1 task todo Login"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, convertErr := ConvertGithub([]byte(test.input))
+			if convertErr != nil {
+				t.Fatal(convertErr)
+			}
+
+			issues, edges := summarize(report)
+			if strings.Join(issues, "\n") != strings.Join(test.issues, "\n") {
+				t.Errorf("issues are\n%s\nwant\n%s", strings.Join(issues, "\n"), strings.Join(test.issues, "\n"))
+			}
+
+			if strings.Join(edges, "\n") != strings.Join(test.edges, "\n") {
+				t.Errorf("edges are\n%s\nwant\n%s", strings.Join(edges, "\n"), strings.Join(test.edges, "\n"))
+			}
+		})
+	}
+}
+
+func TestConvertGithubWarnsAboutMissingIssues(t *testing.T) {
+	report, convertErr := ConvertGithub([]byte(`[{"number": 1, "title": "Login", "state": "open", "body": "blocked by #7"}]`))
+	if convertErr != nil {
+		t.Fatal(convertErr)
+	}
+
+	if len(report.Project.Edges) != 0 || len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "#7") {
+		t.Errorf("expected the link to #7 to be dropped with a warning, got %v and %v", report.Project.Edges, report.Warnings)
+	}
+
+	if _, invalidErr := ConvertGithub([]byte(`{"number": 1}`)); invalidErr == nil {
+		t.Error("expected a file that isn't a list of issues to be rejected")
+	}
+}
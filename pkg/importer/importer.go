@@ -0,0 +1,219 @@
+package importer
+
+import (
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**
+Converts issue trackers' export files into a fileSystem.ExportedProject so they
+are imported through FileSystem.Import like a pm export. Nothing here reaches
+the network, every importer works from a file the tracker already exported.
+
+Trackers refer to issues by their own ids (#12, PROJ-7). Issues and edges are
+collected by those ids first and turned into issue names once everything is read,
+links to issues missing from the export are dropped with a warning.
+*/
+
+// What an import would create, Warnings lists what had to be dropped or renamed
+type Report struct {
+	Project  fileSystem.ExportedProject
+	Warnings []string
+}
+
+type externalIssue struct {
+	id       string
+	title    string
+	fileType string
+	body     string
+	status   string
+	source   string
+}
+
+type externalEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+type projectBuilder struct {
+	issues   []externalIssue
+	ids      map[string]bool
+	edges    []externalEdge
+	warnings []string
+}
+
+func newProjectBuilder() *projectBuilder {
+	return &projectBuilder{
+		ids: map[string]bool{},
+	}
+}
+
+func (pb *projectBuilder) addIssue(issue externalIssue) {
+	if pb.ids[issue.id] {
+		pb.warn("Skipped duplicate issue " + issue.id)
+		return
+	}
+
+	pb.ids[issue.id] = true
+	pb.issues = append(pb.issues, issue)
+}
+
+func (pb *projectBuilder) addEdge(from string, to string, label string) {
+	pb.edges = append(pb.edges, externalEdge{from: from, to: to, label: label})
+}
+
+func (pb *projectBuilder) warn(warning string) {
+	pb.warnings = append(pb.warnings, warning)
+}
+
+func (pb *projectBuilder) hasChildren(id string) bool {
+	for _, edge := range pb.edges {
+		if edge.from == id && edge.label == fileSystem.FILE_RELATIONSHIPS_HIERARCHY {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Issue names double as blob file names so path separators are replaced
+func issueName(title string, id string) string {
+	name := strings.TrimSpace(title)
+	name = strings.NewReplacer("/", "-", "\\", "-", "\n", " ", "\r", "").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return id
+	}
+
+	return name
+}
+
+func (pb *projectBuilder) report() Report {
+	project := fileSystem.ExportedProject{
+		Version: fileSystem.EXPORT_SCHEMA_VERSION,
+		Issues:  []fileSystem.ExportedIssue{},
+		Edges:   []fileSystem.ExportedEdge{},
+	}
+
+	names := map[string]string{}
+	taken := map[string]bool{}
+	for _, issue := range pb.issues {
+		name := issueName(issue.title, issue.id)
+		if taken[name] {
+			renamed := name + " (" + issue.id + ")"
+			pb.warn("Renamed " + issue.id + " to \"" + renamed + "\", \"" + name + "\" is used by another issue")
+			name = renamed
+		}
+
+		taken[name] = true
+		names[issue.id] = name
+
+		meta := map[string]string{
+			pmfile.FILE_META_SOURCE: issue.source,
+		}
+
+		if issue.status != "" && issue.status != pmfile.FILE_STATUS_TODO {
+			meta[pmfile.FILE_META_STATUS] = issue.status
+		}
+
+		project.Issues = append(project.Issues, fileSystem.ExportedIssue{
+			Name: name,
+			Type: issue.fileType,
+			Body: issue.body,
+			Meta: meta,
+		})
+	}
+
+	seen := map[externalEdge]bool{}
+	parents := map[string]string{}
+	for _, edge := range pb.edges {
+		if seen[edge] || edge.from == edge.to {
+			continue
+		}
+
+		seen[edge] = true
+		if !pb.ids[edge.from] || !pb.ids[edge.to] {
+			pb.warn("Dropped " + strings.ToLower(edge.label) + " link between " + edge.from + " and " + edge.to + ", the issue is not in the export")
+			continue
+		}
+
+		if edge.label == fileSystem.FILE_RELATIONSHIPS_HIERARCHY {
+			if parent, ok := parents[edge.to]; ok {
+				pb.warn("Dropped parent " + edge.from + " of " + edge.to + ", it is already a child of " + parent)
+				continue
+			}
+
+			parents[edge.to] = edge.from
+		}
+
+		project.Edges = append(project.Edges, fileSystem.ExportedEdge{
+			From:  names[edge.from],
+			To:    names[edge.to],
+			Label: edge.label,
+		})
+	}
+
+	return Report{
+		Project:  project,
+		Warnings: pb.warnings,
+	}
+}
+
+// Human readable summary of what the import creates
+func (r Report) String() string {
+	types := map[string]int{}
+	for _, issue := range r.Project.Issues {
+		types[issue.Type]++
+	}
+
+	labels := map[string]int{}
+	for _, edge := range r.Project.Edges {
+		labels[edge.Label]++
+	}
+
+	var builder strings.Builder
+	builder.WriteString(strconv.Itoa(len(r.Project.Issues)) + " issues:")
+	for _, fileType := range pmfile.FILE_TYPE_HIERARCHY {
+		builder.WriteString(" " + strconv.Itoa(types[fileType]) + " " + fileType)
+	}
+
+	builder.WriteString("\n" + strconv.Itoa(labels[fileSystem.FILE_RELATIONSHIPS_HIERARCHY]) + " hierarchy links, ")
+	builder.WriteString(strconv.Itoa(labels[fileSystem.FILE_RELATIONSHIP_DEPENDENCY]) + " dependency links\n")
+
+	issues := append([]fileSystem.ExportedIssue{}, r.Project.Issues...)
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Meta[pmfile.FILE_META_SOURCE] < issues[j].Meta[pmfile.FILE_META_SOURCE]
+	})
+
+	builder.WriteString("\n")
+	for _, issue := range issues {
+		status, ok := issue.Meta[pmfile.FILE_META_STATUS]
+		if !ok {
+			status = pmfile.FILE_STATUS_TODO
+		}
+
+		builder.WriteString("  " + issue.Meta[pmfile.FILE_META_SOURCE] + "\t" + issue.Type + "\t" + status + "\t" + issue.Name + "\n")
+	}
+
+	for _, edge := range r.Project.Edges {
+		relation := "contains"
+		if edge.Label == fileSystem.FILE_RELATIONSHIP_DEPENDENCY {
+			relation = "blocks"
+		}
+
+		builder.WriteString("  " + edge.From + " " + relation + " " + edge.To + "\n")
+	}
+
+	if len(r.Warnings) > 0 {
+		builder.WriteString("\nWarnings:\n")
+		for _, warning := range r.Warnings {
+			builder.WriteString("  " + warning + "\n")
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,236 @@
+package importer
+
+import (
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+/**
+Reads the CSV export of a Jira issue search (Export > CSV (all fields)).
+
+Jira repeats a column once per value, so every column is read as a list.
+Epics, parents and sub-tasks become hierarchy edges and "Blocks" issue links
+become dependency edges. Descriptions are converted from Jira wiki markup to markdown.
+*/
+
+type jiraRow map[string][]string
+
+func (row jiraRow) first(columns ...string) string {
+	for _, column := range columns {
+		for _, value := range row[column] {
+			if strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+
+	return ""
+}
+
+func (row jiraRow) all(column string) []string {
+	var values []string
+	for _, value := range row[column] {
+		if strings.TrimSpace(value) != "" {
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+
+	return values
+}
+
+func ConvertJira(data []byte) (Report, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, readErr := reader.ReadAll()
+	if readErr != nil {
+		return Report{}, errors.New("Invalid Jira CSV file: " + readErr.Error())
+	}
+
+	if len(records) == 0 {
+		return Report{}, errors.New("Invalid Jira CSV file: missing header")
+	}
+
+	header := records[0]
+	if indexOf(header, "Issue key") == -1 || indexOf(header, "Summary") == -1 {
+		return Report{}, errors.New("Invalid Jira CSV file: expected Issue key and Summary columns")
+	}
+
+	var rows []jiraRow
+	for _, record := range records[1:] {
+		row := jiraRow{}
+		for index, value := range record {
+			if index < len(header) {
+				row[header[index]] = append(row[header[index]], value)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	// Parents are referenced by key in newer exports and by id in older ones
+	keys := map[string]string{}
+	for _, row := range rows {
+		if id := row.first("Issue id"); id != "" {
+			keys[id] = row.first("Issue key")
+		}
+	}
+
+	parentKey := func(value string) string {
+		if key, ok := keys[value]; ok {
+			return key
+		}
+
+		return value
+	}
+
+	builder := newProjectBuilder()
+	issueTypes := map[string]string{}
+	for _, row := range rows {
+		key := row.first("Issue key")
+		if key == "" {
+			builder.warn("Skipped row without an issue key: " + row.first("Summary"))
+			continue
+		}
+
+		if parent := row.first("Parent key", "Parent", "Parent id", "Custom field (Epic Link)"); parent != "" {
+			builder.addEdge(parentKey(parent), key, fileSystem.FILE_RELATIONSHIPS_HIERARCHY)
+		}
+
+		for _, blocked := range row.all("Outward issue link (Blocks)") {
+			builder.addEdge(key, blocked, fileSystem.FILE_RELATIONSHIP_DEPENDENCY)
+		}
+
+		for _, blocker := range row.all("Inward issue link (Blocks)") {
+			builder.addEdge(blocker, key, fileSystem.FILE_RELATIONSHIP_DEPENDENCY)
+		}
+
+		issueTypes[key] = strings.ToLower(row.first("Issue Type"))
+		summary := row.first("Summary")
+		builder.addIssue(externalIssue{
+			id:     key,
+			title:  summary,
+			body:   jiraBody(summary, row.first("Description")),
+			status: jiraStatus(row.first("Status Category"), row.first("Status")),
+			source: "jira:" + key,
+		})
+	}
+
+	for index := range builder.issues {
+		issue := &builder.issues[index]
+		switch issueTypes[issue.id] {
+		case "epic":
+			issue.fileType = pmfile.FILE_TYPE_EPIC
+		case "story":
+			issue.fileType = pmfile.FILE_TYPE_STORY
+		case "sub-task", "subtask":
+			issue.fileType = pmfile.FILE_TYPE_TASK
+		default:
+			// Tasks and bugs with sub-tasks sit between epics and sub-tasks
+			issue.fileType = pmfile.FILE_TYPE_TASK
+			if builder.hasChildren(issue.id) {
+				issue.fileType = pmfile.FILE_TYPE_STORY
+			}
+		}
+	}
+
+	return builder.report(), nil
+}
+
+func jiraStatus(category string, status string) string {
+	switch strings.ToLower(category) {
+	case "done":
+		return pmfile.FILE_STATUS_DONE
+	case "in progress":
+		return pmfile.FILE_STATUS_IN_PROGRESS
+	case "to do":
+		return pmfile.FILE_STATUS_TODO
+	}
+
+	switch strings.ToLower(status) {
+	case "done", "closed", "resolved":
+		return pmfile.FILE_STATUS_DONE
+	case "in progress", "in review":
+		return pmfile.FILE_STATUS_IN_PROGRESS
+	}
+
+	return pmfile.FILE_STATUS_TODO
+}
+
+func jiraBody(summary string, description string) string {
+	markdown := strings.TrimSpace(jiraToMarkdown(description))
+	if markdown == "" {
+		return "# " + summary + "\n"
+	}
+
+	return "# " + summary + "\n\n" + markdown + "\n"
+}
+
+var jiraHeading = regexp.MustCompile(`^h([1-6])\.\s*`)
+var jiraBullet = regexp.MustCompile(`^([*\-]+)\s+`)
+var jiraNumbered = regexp.MustCompile(`^(#+)\s+`)
+var jiraCodeBlock = regexp.MustCompile(`^\{(code|noformat)(?::([^}|]*)[^}]*)?\}`)
+var jiraLink = regexp.MustCompile(`\[([^|\]]+)\|([^\]]+)\]`)
+var jiraBareLink = regexp.MustCompile(`\[(https?://[^\]|]+)\]`)
+var jiraBold = regexp.MustCompile(`(^|[\s(])\*([^*\s][^*]*?)\*`)
+var jiraItalic = regexp.MustCompile(`(^|[\s(])_([^_\s][^_]*?)_`)
+var jiraMonospace = regexp.MustCompile(`\{\{(.+?)\}\}`)
+
+// Converts the common parts of Jira wiki markup, anything else is kept as written
+func jiraToMarkdown(wiki string) string {
+	lines := strings.Split(strings.ReplaceAll(wiki, "\r\n", "\n"), "\n")
+	var converted []string
+	inCode := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if match := jiraCodeBlock.FindStringSubmatch(trimmed); match != nil {
+			if inCode {
+				converted = append(converted, "```")
+			} else {
+				converted = append(converted, "```"+strings.TrimSpace(match[2]))
+			}
+
+			inCode = !inCode
+			continue
+		}
+
+		if inCode {
+			converted = append(converted, line)
+			continue
+		}
+
+		if match := jiraHeading.FindStringSubmatch(trimmed); match != nil {
+			trimmed = strings.Repeat("#", int(match[1][0]-'0')) + " " + trimmed[len(match[0]):]
+		} else if match := jiraBullet.FindStringSubmatch(trimmed); match != nil {
+			trimmed = strings.Repeat("  ", len(match[1])-1) + "- " + jiraInline(trimmed[len(match[0]):])
+		} else if match := jiraNumbered.FindStringSubmatch(trimmed); match != nil {
+			trimmed = strings.Repeat("   ", len(match[1])-1) + "1. " + jiraInline(trimmed[len(match[0]):])
+		} else {
+			trimmed = jiraInline(trimmed)
+		}
+
+		converted = append(converted, trimmed)
+	}
+
+	if inCode {
+		converted = append(converted, "```")
+	}
+
+	return strings.Join(converted, "\n")
+}
+
+func jiraInline(text string) string {
+	text = jiraMonospace.ReplaceAllString(text, "`$1`")
+	text = jiraLink.ReplaceAllString(text, "[$1]($2)")
+	text = jiraBareLink.ReplaceAllString(text, "<$1>")
+	text = jiraBold.ReplaceAllString(text, "$1**$2**")
+	text = jiraItalic.ReplaceAllString(text, "$1*$2*")
+	return text
+}
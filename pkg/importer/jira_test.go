@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github/pm/pkg/fileSystem"
+)
+
+const jiraHeader = "Summary,Issue key,Issue id,Issue Type,Status,Status Category,Parent,Custom field (Epic Link),Outward issue link (Blocks),Outward issue link (Blocks),Description\n"
+
+func TestConvertJira(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		issues []string
+		edges  []string
+	}{
+		{
+			name: "types and statuses",
+			input: jiraHeader +
+				"Launch,PM-1,1001,Epic,Open,To Do,,,,,\n" +
+				"Auth,PM-2,1002,Task,In Review,,,PM-1,,,\n" +
+				"Login,PM-3,1003,Sub-task,Closed,Done,1002,,,,\n" +
+				"Logout,PM-4,1004,Bug,Backlog,To Do,,,,,\n",
+			issues: []string{
+				"jira:PM-1 epic todo Launch",
+				"jira:PM-2 story in-progress Auth",
+				"jira:PM-3 task done Login",
+				"jira:PM-4 task todo Logout",
+			},
+			edges: []string{
+				"Launch HIERARCHY Auth",
+				"Auth HIERARCHY Login",
+			},
+		},
+		{
+			name: "sanitized names",
+			input: jiraHeader +
+				"CI/CD pipeline,PM-1,1001,Task,Open,To Do,,,,,\n" +
+				"..,PM-2,1002,Task,Open,To Do,,,,,\n",
+			issues: []string{
+				"jira:PM-1 task todo CI-CD pipeline",
+				"jira:PM-2 task todo PM-2",
+			},
+		},
+		{
+			name: "blocks links",
+			input: jiraHeader +
+				"Tokens,PM-1,1001,Task,Open,To Do,,,PM-2,PM-3,\n" +
+				"Sessions,PM-2,1002,Task,Open,To Do,,,PM-3,,\n" +
+				"Login,PM-3,1003,Task,Open,To Do,,,PM-9,,\n",
+			issues: []string{
+				"jira:PM-1 task todo Tokens",
+				"jira:PM-2 task todo Sessions",
+				"jira:PM-3 task todo Login",
+			},
+			edges: []string{
+				"Tokens DEPENDENCY Sessions",
+				"Tokens DEPENDENCY Login",
+				"Sessions DEPENDENCY Login",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, convertErr := ConvertJira([]byte(test.input))
+			if convertErr != nil {
+				t.Fatal(convertErr)
+			}
+
+			issues, edges := summarize(report)
+			if strings.Join(issues, "\n") != strings.Join(test.issues, "\n") {
+				t.Errorf("issues are\n%s\nwant\n%s", strings.Join(issues, "\n"), strings.Join(test.issues, "\n"))
+			}
+
+			if strings.Join(edges, "\n") != strings.Join(test.edges, "\n") {
+				t.Errorf("edges are\n%s\nwant\n%s", strings.Join(edges, "\n"), strings.Join(test.edges, "\n"))
+			}
+		})
+	}
+}
+
+func TestJiraToMarkdown(t *testing.T) {
+	tests := []struct {
+		wiki     string
+		markdown string
+	}{
+		{"h2. Goal", "## Goal"},
+		{"* one\n** two", "- one\n  - two"},
+		{"# first\n## second", "1. first\n   1. second"},
+		{"*bold* and _italic_ with {{code}}", "**bold** and *italic* with `code`"},
+		{"[docs|https://example.com] or [https://example.com]", "[docs](https://example.com) or <https://example.com>"},
+		{"{code:go}\nfmt.Println(\"*x*\")\n{code}", "```go\nfmt.Println(\"*x*\")\n```"},
+	}
+
+	for _, test := range tests {
+		if markdown := jiraToMarkdown(test.wiki); markdown != test.markdown {
+			t.Errorf("%q converts to %q, want %q", test.wiki, markdown, test.markdown)
+		}
+	}
+}
+
+func TestJiraImportRejectsInvalidHierarchy(t *testing.T) {
+	fs := fileSystem.NewFileSystem(filepath.Join(t.TempDir(), fileSystem.PROJECT_DIRECTORY))
+	if bootErr := fs.Boot(); bootErr != nil {
+		t.Fatal(bootErr)
+	}
+
+	// A sub-task becomes a task, which can't contain the epic put under it
+	report, convertErr := ConvertJira([]byte(jiraHeader +
+		"Login,PM-1,1001,Sub-task,Open,To Do,,,,,\n" +
+		"Launch,PM-2,1002,Epic,Open,To Do,PM-1,,,,\n"))
+	if convertErr != nil {
+		t.Fatal(convertErr)
+	}
+
+	if importErr := fs.Import(report.Project); !errors.Is(importErr, fileSystem.ErrInvalid) {
+		t.Fatalf("expected the import to be invalid, got %v", importErr)
+	}
+
+	if _, typeErr := fs.GetFileType("Login"); typeErr == nil {
+		t.Error("the rejected import created Login")
+	}
+}
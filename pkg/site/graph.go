@@ -0,0 +1,135 @@
+package site
+
+import (
+	"github/pm/pkg/fileSystem"
+
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	nodeWidth   = 180
+	nodeHeight  = 36
+	columnGap   = 60
+	rowGap      = 16
+	graphMargin = 10
+	labelLength = 24
+)
+
+// Lays the issues that take part in a dependency out in columns, every issue
+// is placed one column to the right of its furthest upstream dependency
+func dependencyGraphSvg(project fileSystem.ExportedProject, issues map[string]*issuePage) string {
+	downstream := map[string][]string{}
+	inDegree := map[string]int{}
+	for _, edge := range project.Edges {
+		if edge.Label != fileSystem.FILE_RELATIONSHIP_DEPENDENCY {
+			continue
+		}
+
+		downstream[edge.From] = append(downstream[edge.From], edge.To)
+		inDegree[edge.To]++
+		if _, ok := inDegree[edge.From]; !ok {
+			inDegree[edge.From] = 0
+		}
+	}
+
+	if len(inDegree) == 0 {
+		return "<p>No dependencies between issues.</p>"
+	}
+
+	var queue []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	depth := map[string]int{}
+	remaining := map[string]int{}
+	for name, degree := range inDegree {
+		remaining[name] = degree
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, next := range downstream[name] {
+			depth[next] = max(depth[next], depth[name]+1)
+			remaining[next]--
+			if remaining[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	var columns [][]string
+	for name := range inDegree {
+		for len(columns) <= depth[name] {
+			columns = append(columns, nil)
+		}
+
+		columns[depth[name]] = append(columns[depth[name]], name)
+	}
+
+	type point struct{ x, y int }
+	positions := map[string]point{}
+	rows := 0
+	for column, names := range columns {
+		sort.Strings(names)
+		rows = max(rows, len(names))
+		for row, name := range names {
+			positions[name] = point{
+				x: graphMargin + column*(nodeWidth+columnGap),
+				y: graphMargin + row*(nodeHeight+rowGap),
+			}
+		}
+	}
+
+	width := 2*graphMargin + len(columns)*nodeWidth + (len(columns)-1)*columnGap
+	height := 2*graphMargin + rows*nodeHeight + (rows-1)*rowGap
+
+	var svg strings.Builder
+	svg.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + strconv.Itoa(width) + `" height="` + strconv.Itoa(height) + `" viewBox="0 0 ` + strconv.Itoa(width) + " " + strconv.Itoa(height) + `">` + "\n")
+	svg.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" style="fill: #57606a; stroke: none"/></marker></defs>` + "\n")
+
+	froms := make([]string, 0, len(downstream))
+	for name := range downstream {
+		froms = append(froms, name)
+	}
+	sort.Strings(froms)
+
+	for _, from := range froms {
+		targets := append([]string{}, downstream[from]...)
+		sort.Strings(targets)
+		for _, to := range targets {
+			start := point{positions[from].x + nodeWidth, positions[from].y + nodeHeight/2}
+			end := point{positions[to].x, positions[to].y + nodeHeight/2}
+			middle := (start.x + end.x) / 2
+			svg.WriteString(`<path d="M ` + strconv.Itoa(start.x) + " " + strconv.Itoa(start.y) +
+				" C " + strconv.Itoa(middle) + " " + strconv.Itoa(start.y) +
+				" " + strconv.Itoa(middle) + " " + strconv.Itoa(end.y) +
+				" " + strconv.Itoa(end.x) + " " + strconv.Itoa(end.y) + `" marker-end="url(#arrow)"/>` + "\n")
+		}
+	}
+
+	for _, names := range columns {
+		for _, name := range names {
+			issue := issues[name]
+			position := positions[name]
+			label := []rune(name)
+			if len(label) > labelLength {
+				label = append(label[:labelLength-1], '…')
+			}
+
+			svg.WriteString(`<a href="` + html.EscapeString(issue.Href) + `" class="` + html.EscapeString(issue.Status) + `">`)
+			svg.WriteString("<title>" + html.EscapeString(name+" ("+issue.Type+", "+issue.Status+")") + "</title>")
+			svg.WriteString(`<rect x="` + strconv.Itoa(position.x) + `" y="` + strconv.Itoa(position.y) + `" width="` + strconv.Itoa(nodeWidth) + `" height="` + strconv.Itoa(nodeHeight) + `" rx="6"/>`)
+			svg.WriteString(`<text x="` + strconv.Itoa(position.x+10) + `" y="` + strconv.Itoa(position.y+nodeHeight/2+4) + `">` + html.EscapeString(string(label)) + "</text></a>\n")
+		}
+	}
+
+	svg.WriteString("</svg>")
+	return svg.String()
+}
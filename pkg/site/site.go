@@ -0,0 +1,285 @@
+package site
+
+import (
+	pmfile "github/pm/pkg/file"
+	"github/pm/pkg/fileSystem"
+
+	"bytes"
+	"errors"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+/**
+Renders a project into a static HTML site that can be published without pm:
+
+	index.html          overview with the dependency graph
+	epics.html ...      one index page per issue type
+	issues/<slug>.html  one page per issue with its relationships
+	style.css
+
+Pages are rendered from an export of the project so the site always shows
+one consistent state. Raw HTML in issue bodies is escaped, not rendered.
+*/
+
+type issueLink struct {
+	Name   string
+	Href   string
+	Type   string
+	Status string
+}
+
+type issuePage struct {
+	issueLink
+	Priority   string
+	Estimate   string
+	Source     string
+	Body       template.HTML
+	Parents    []issueLink
+	Children   []issueLink
+	Upstream   []issueLink
+	Downstream []issueLink
+}
+
+type typeIndex struct {
+	Type   string
+	Title  string
+	Href   string
+	Issues []issuePage
+}
+
+type site struct {
+	Title  string
+	Types  []typeIndex
+	Issues map[string]*issuePage
+	Graph  template.HTML
+}
+
+var typeTitles = map[string]string{
+	pmfile.FILE_TYPE_EPIC:  "Epics",
+	pmfile.FILE_TYPE_STORY: "Stories",
+	pmfile.FILE_TYPE_TASK:  "Tasks",
+}
+
+// Writes the site for project into outDir, existing files with the same names are overwritten
+func Build(project fileSystem.ExportedProject, title string, outDir string) error {
+	s, siteErr := newSite(project, title)
+	if siteErr != nil {
+		return siteErr
+	}
+
+	mkdirErr := os.MkdirAll(filepath.Join(outDir, "issues"), 0755)
+	if mkdirErr != nil {
+		return mkdirErr
+	}
+
+	writeErr := os.WriteFile(filepath.Join(outDir, "style.css"), []byte(stylesheet), 0644)
+	if writeErr != nil {
+		return writeErr
+	}
+
+	renderErr := render(filepath.Join(outDir, "index.html"), "index", "", s)
+	if renderErr != nil {
+		return renderErr
+	}
+
+	for _, index := range s.Types {
+		renderErr := render(filepath.Join(outDir, index.Href), "type", "", struct {
+			Site  *site
+			Index typeIndex
+		}{s, index})
+		if renderErr != nil {
+			return renderErr
+		}
+	}
+
+	for _, page := range s.Issues {
+		renderErr := render(filepath.Join(outDir, page.Href), "issue", "../", struct {
+			Site  *site
+			Issue *issuePage
+		}{s, page})
+		if renderErr != nil {
+			return renderErr
+		}
+	}
+
+	return nil
+}
+
+func newSite(project fileSystem.ExportedProject, title string) (*site, error) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(renderer.WithNodeRenderers(util.Prioritized(escapedHTML{}, 100))),
+	)
+
+	s := &site{
+		Title:  title,
+		Issues: map[string]*issuePage{},
+	}
+
+	slugs := map[string]bool{}
+	for _, issue := range project.Issues {
+		slug := slugify(issue.Name)
+		for suffix := 2; slugs[slug]; suffix++ {
+			slug = slugify(issue.Name) + "-" + strconv.Itoa(suffix)
+		}
+		slugs[slug] = true
+
+		status := issue.Meta[pmfile.FILE_META_STATUS]
+		if status == "" {
+			status = pmfile.FILE_STATUS_TODO
+		}
+
+		var body bytes.Buffer
+		convertErr := markdown.Convert([]byte(fileSystem.BodyWithoutTitle(issue.Body, issue.Name)), &body)
+		if convertErr != nil {
+			return nil, errors.New("Cannot render " + issue.Name + ": " + convertErr.Error())
+		}
+
+		s.Issues[issue.Name] = &issuePage{
+			issueLink: issueLink{
+				Name:   issue.Name,
+				Href:   "issues/" + slug + ".html",
+				Type:   issue.Type,
+				Status: status,
+			},
+			Priority: issue.Meta[pmfile.FILE_META_PRIORITY],
+			Estimate: issue.Meta[pmfile.FILE_META_ESTIMATE],
+			Source:   issue.Meta[pmfile.FILE_META_SOURCE],
+			Body:     template.HTML(body.String()),
+		}
+	}
+
+	for _, edge := range project.Edges {
+		from, fromOk := s.Issues[edge.From]
+		to, toOk := s.Issues[edge.To]
+		if !fromOk || !toOk {
+			return nil, errors.New("Edge between unknown issues " + edge.From + " and " + edge.To)
+		}
+
+		if edge.Label == fileSystem.FILE_RELATIONSHIPS_HIERARCHY {
+			from.Children = append(from.Children, to.issueLink)
+			to.Parents = append(to.Parents, from.issueLink)
+		} else {
+			from.Downstream = append(from.Downstream, to.issueLink)
+			to.Upstream = append(to.Upstream, from.issueLink)
+		}
+	}
+
+	for _, fileType := range pmfile.FILE_TYPE_HIERARCHY {
+		title, ok := typeTitles[fileType]
+		if !ok {
+			// Issue types configured by the project
+			title = strings.ToUpper(fileType[:1]) + fileType[1:] + "s"
+		}
+
+		index := typeIndex{
+			Type:  fileType,
+			Title: title,
+			Href:  title + ".html",
+		}
+		index.Href = strings.ToLower(index.Href)
+
+		for _, issue := range project.Issues {
+			if issue.Type == fileType {
+				index.Issues = append(index.Issues, *s.Issues[issue.Name])
+			}
+		}
+
+		sort.Slice(index.Issues, func(i, j int) bool {
+			return index.Issues[i].Name < index.Issues[j].Name
+		})
+
+		s.Types = append(s.Types, index)
+	}
+
+	s.Graph = template.HTML(dependencyGraphSvg(project, s.Issues))
+	return s, nil
+}
+
+// Renders raw HTML in issue bodies as text instead of leaving it out
+type escapedHTML struct{}
+
+func (escapedHTML) RegisterFuncs(registerer renderer.NodeRendererFuncRegisterer) {
+	registerer.Register(ast.KindRawHTML, renderRawHTML)
+	registerer.Register(ast.KindHTMLBlock, renderHTMLBlock)
+}
+
+func renderRawHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		segments := node.(*ast.RawHTML).Segments
+		for i := 0; i < segments.Len(); i++ {
+			segment := segments.At(i)
+			w.WriteString(html.EscapeString(string(segment.Value(source))))
+		}
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+func renderHTMLBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	block := node.(*ast.HTMLBlock)
+	if !entering {
+		if block.HasClosure() {
+			closure := block.ClosureLine
+			w.WriteString(html.EscapeString(string(closure.Value(source))))
+		}
+
+		w.WriteString("</p>\n")
+		return ast.WalkContinue, nil
+	}
+
+	w.WriteString("<p>")
+	for i := 0; i < block.Lines().Len(); i++ {
+		line := block.Lines().At(i)
+		w.WriteString(html.EscapeString(string(line.Value(source))))
+	}
+
+	return ast.WalkContinue, nil
+}
+
+// File name safe version of an issue name
+func slugify(name string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			slug.WriteRune(r)
+			dash = false
+		} else if !dash && slug.Len() > 0 {
+			slug.WriteRune('-')
+			dash = true
+		}
+	}
+
+	result := strings.TrimSuffix(slug.String(), "-")
+	if result == "" {
+		return "issue"
+	}
+
+	return result
+}
+
+func render(path string, name string, root string, data any) error {
+	var output bytes.Buffer
+	executeErr := pages.ExecuteTemplate(&output, name, struct {
+		Root string
+		Data any
+	}{root, data})
+	if executeErr != nil {
+		return executeErr
+	}
+
+	return os.WriteFile(path, output.Bytes(), 0644)
+}
@@ -0,0 +1,98 @@
+package site
+
+import (
+	"github/pm/pkg/fileSystem"
+
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readPage(t *testing.T, dir string, name string) string {
+	t.Helper()
+
+	page, readErr := os.ReadFile(filepath.Join(dir, name))
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	return string(page)
+}
+
+func TestBuild(t *testing.T) {
+	project := fileSystem.ExportedProject{
+		Version: fileSystem.EXPORT_SCHEMA_VERSION,
+		Issues: []fileSystem.ExportedIssue{
+			{Name: "Auth", Type: "story"},
+			{Name: "Login form", Type: "task", Body: "# Login form\n\n<script>alert(1)</script>\n\nEmail *and* <b>password</b>\n"},
+			{Name: "Login-form", Type: "task", Meta: map[string]string{"status": "done"}},
+			{Name: "Sessions", Type: "task"},
+		},
+		Edges: []fileSystem.ExportedEdge{
+			{From: "Auth", To: "Login form", Label: fileSystem.FILE_RELATIONSHIPS_HIERARCHY},
+			{From: "Auth", To: "Sessions", Label: fileSystem.FILE_RELATIONSHIPS_HIERARCHY},
+			{From: "Login form", To: "Sessions", Label: fileSystem.FILE_RELATIONSHIP_DEPENDENCY},
+		},
+	}
+
+	dir := t.TempDir()
+	if buildErr := Build(project, "Launch", dir); buildErr != nil {
+		t.Fatal(buildErr)
+	}
+
+	index := readPage(t, dir, "index.html")
+	for _, want := range []string{`<a href="epics.html">Epics</a> (0)`, `<a href="stories.html">Stories</a> (1)`, `<a href="tasks.html">Tasks</a> (3)`, "<svg ", `href="issues/login-form.html"`} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html does not contain %s", want)
+		}
+	}
+
+	if epics := readPage(t, dir, "epics.html"); !strings.Contains(epics, "No epic issues.") {
+		t.Error("epics.html does not say that there are no epics")
+	}
+
+	tasks := readPage(t, dir, "tasks.html")
+	for _, want := range []string{`<a href="issues/login-form.html">Login form</a>`, `<a href="issues/login-form-2.html">Login-form</a>`, `<a href="issues/sessions.html">Sessions</a>`, `<a href="issues/auth.html">Auth</a>`} {
+		if !strings.Contains(tasks, want) {
+			t.Errorf("tasks.html does not contain %s", want)
+		}
+	}
+
+	loginForm := readPage(t, dir, "issues/login-form.html")
+	for _, want := range []string{"<h1>Login form</h1>", "<em>and</em>", "&lt;b&gt;password&lt;/b&gt;", "<p>&lt;script&gt;alert(1)&lt;/script&gt;\n</p>", "<h3>Parent</h3>\n<ul>\n<li><a href=\"../issues/auth.html\">Auth</a>", "<h3>Blocks</h3>\n<ul>\n<li><a href=\"../issues/sessions.html\">Sessions</a>"} {
+		if !strings.Contains(loginForm, want) {
+			t.Errorf("login-form.html does not contain %s", want)
+		}
+	}
+
+	if strings.Contains(loginForm, "<script>") {
+		t.Error("login-form.html renders the raw html of the body")
+	}
+
+	sessions := readPage(t, dir, "issues/sessions.html")
+	if !strings.Contains(sessions, "<h3>Blocked by</h3>\n<ul>\n<li><a href=\"../issues/login-form.html\">Login form</a>") {
+		t.Error("sessions.html does not link to the issue blocking it")
+	}
+
+	auth := readPage(t, dir, "issues/auth.html")
+	if !strings.Contains(auth, "<h3>Children</h3>\n<ul>\n<li><a href=\"../issues/login-form.html\">Login form</a> <span class=\"badge todo\">todo</span></li>\n<li><a href=\"../issues/sessions.html\">Sessions</a>") {
+		t.Error("auth.html does not link to its children")
+	}
+
+	if loginForm2 := readPage(t, dir, "issues/login-form-2.html"); !strings.Contains(loginForm2, "<h1>Login-form</h1>") {
+		t.Error("login-form-2.html is not the page of Login-form")
+	}
+}
+
+func TestBuildRejectsEdgesBetweenUnknownIssues(t *testing.T) {
+	project := fileSystem.ExportedProject{
+		Version: fileSystem.EXPORT_SCHEMA_VERSION,
+		Issues:  []fileSystem.ExportedIssue{{Name: "Auth", Type: "story"}},
+		Edges:   []fileSystem.ExportedEdge{{From: "Auth", To: "Login", Label: fileSystem.FILE_RELATIONSHIPS_HIERARCHY}},
+	}
+
+	if buildErr := Build(project, "Launch", t.TempDir()); buildErr == nil {
+		t.Error("expected an edge to a missing issue to be rejected")
+	}
+}
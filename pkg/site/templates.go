@@ -0,0 +1,90 @@
+package site
+
+import "html/template"
+
+var pageFuncs = template.FuncMap{
+	"header": func(title string, siteTitle string, types []typeIndex, root string) map[string]any {
+		return map[string]any{"Title": title, "SiteTitle": siteTitle, "Types": types, "Root": root}
+	},
+	"links": func(heading string, links []issueLink, root string) map[string]any {
+		return map[string]any{"Heading": heading, "Links": links, "Root": root}
+	},
+}
+
+var pages = template.Must(template.New("pages").Funcs(pageFuncs).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<nav><a href="{{.Root}}index.html">{{.SiteTitle}}</a>{{range .Types}} <a href="{{$.Root}}{{.Href}}">{{.Title}}</a>{{end}}</nav>
+<main>
+{{end}}
+
+{{define "footer"}}</main>
+</body>
+</html>
+{{end}}
+
+{{define "links"}}{{if .Links}}<h3>{{.Heading}}</h3>
+<ul>{{range .Links}}
+<li><a href="{{$.Root}}{{.Href}}">{{.Name}}</a> <span class="badge {{.Status}}">{{.Status}}</span></li>{{end}}
+</ul>
+{{end}}{{end}}
+
+{{define "index"}}{{template "header" (header .Data.Title .Data.Title .Data.Types .Root)}}<h1>{{.Data.Title}}</h1>
+<ul class="summary">{{range .Data.Types}}
+<li><a href="{{.Href}}">{{.Title}}</a> ({{len .Issues}})</li>{{end}}
+</ul>
+<h2>Dependencies</h2>
+<div class="graph">{{.Data.Graph}}</div>
+{{template "footer"}}{{end}}
+
+{{define "type"}}{{template "header" (header .Data.Index.Title .Data.Site.Title .Data.Site.Types .Root)}}<h1>{{.Data.Index.Title}}</h1>
+{{if .Data.Index.Issues}}<table>
+<thead><tr><th>Issue</th><th>Status</th><th>Priority</th><th>Estimate</th><th>Parent</th></tr></thead>
+<tbody>{{range .Data.Index.Issues}}
+<tr><td><a href="{{.Href}}">{{.Name}}</a></td><td><span class="badge {{.Status}}">{{.Status}}</span></td><td>{{.Priority}}</td><td>{{.Estimate}}</td><td>{{range .Parents}}<a href="{{.Href}}">{{.Name}}</a>{{end}}</td></tr>{{end}}
+</tbody>
+</table>
+{{else}}<p>No {{.Data.Index.Type}} issues.</p>
+{{end}}{{template "footer"}}{{end}}
+
+{{define "issue"}}{{template "header" (header .Data.Issue.Name .Data.Site.Title .Data.Site.Types .Root)}}<h1>{{.Data.Issue.Name}}</h1>
+<p class="meta"><span class="type">{{.Data.Issue.Type}}</span> <span class="badge {{.Data.Issue.Status}}">{{.Data.Issue.Status}}</span>{{with .Data.Issue.Priority}} Priority {{.}}{{end}}{{with .Data.Issue.Estimate}} Estimate {{.}}{{end}}{{with .Data.Issue.Source}} Source {{.}}{{end}}</p>
+<div class="layout">
+<article>{{.Data.Issue.Body}}</article>
+<aside>
+{{template "links" (links "Parent" .Data.Issue.Parents .Root)}}{{template "links" (links "Children" .Data.Issue.Children .Root)}}{{template "links" (links "Blocked by" .Data.Issue.Upstream .Root)}}{{template "links" (links "Blocks" .Data.Issue.Downstream .Root)}}</aside>
+</div>
+{{template "footer"}}{{end}}
+`))
+
+const stylesheet = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #1f2328; }
+nav { background: #24292f; padding: 0.75em 2em; }
+nav a { color: #fff; margin-right: 1.5em; text-decoration: none; }
+main { max-width: 72em; margin: 0 auto; padding: 1em 2em; }
+a { color: #0969da; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.4em 0.8em; border-bottom: 1px solid #d0d7de; }
+pre { background: #f6f8fa; padding: 1em; overflow-x: auto; }
+.layout { display: flex; gap: 2em; }
+.layout article { flex: 1; min-width: 0; }
+.layout aside { width: 18em; }
+.meta .type { text-transform: uppercase; font-size: 0.8em; color: #57606a; }
+.badge { border-radius: 1em; padding: 0.1em 0.6em; font-size: 0.8em; border: 1px solid #d0d7de; }
+.badge.todo { background: #ffffff; }
+.badge.in-progress { background: #fff3b0; }
+.badge.done { background: #c8e6c9; }
+.graph { overflow-x: auto; }
+.graph rect { stroke: #57606a; }
+.graph .todo rect { fill: #ffffff; }
+.graph .in-progress rect { fill: #fff3b0; }
+.graph .done rect { fill: #c8e6c9; }
+.graph text { font-size: 12px; fill: #1f2328; }
+.graph path { fill: none; stroke: #57606a; stroke-dasharray: 5 3; }
+`